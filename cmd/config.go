@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/canalun/sqloth/domain/model"
+	"github.com/pkg/errors"
+	"github.com/spf13/viper"
+)
+
+const defaultRecordNumber = 10
+
+// config is the structure of the config file. the flags bound to viper override the same keys.
+//
+//	recordNumber: 100
+//	cardinality: uniform
+//	nullRate: 0.1
+//	length: 1-20
+//	omitDefaults: false
+//	defaultRate: 0.2
+//	specialFloatRate: 0.05
+//	srid: 4326
+//	boundingBox: 139.56,35.53,139.92,35.82
+//	seed: 42
+//	dialect: mysql
+//	format: sql
+//	batchSize: 1000
+//	maxStatementBytes: 1048576
+//	tableOrder: dependency
+//	breakCycles: true
+//	hierarchyDepth: 4
+//	tables:
+//	  country:
+//	    recordNumber: 50
+//	  product:
+//	    columns:
+//	      owner:
+//	        cardinality: exact:3
+//	      description:
+//	        nullRate: 0.5
+//	      code:
+//	        length: 8
+//	      status:
+//	        weights:
+//	          published: 0.9
+//	  store:
+//	    columns:
+//	      location:
+//	        boundingBox: 135.4,34.6,135.6,34.8
+type config struct {
+	RecordNumber string
+	Cardinality  string
+	NullRate     float64
+	// Length is the range of the lengths of strings and binary data, e.g. 1-20 or 8
+	Length       string
+	OmitDefaults bool
+	DefaultRate  float64
+	// SpecialFloatRate is the rate of 0, -0 and the min and the max magnitude in FLOAT and DOUBLE columns
+	SpecialFloatRate float64
+	// SRID is the spatial reference system of the spatial columns without SRID attributes
+	SRID int
+	// BoundingBox is the area of the values of spatial columns, in min x, min y, max x and max y
+	BoundingBox string
+	Seed        int64
+	Dialect     string
+	Format      string
+	BatchSize   int
+	// MaxStatementBytes is the max size of an INSERT statement, e.g. max_allowed_packet of MySQL
+	MaxStatementBytes int
+	TableOrder        string
+	BreakCycles       bool
+	HierarchyDepth    int
+	Tables            map[string]tableConfig
+}
+
+type tableConfig struct {
+	RecordNumber int
+	Columns      map[string]columnConfig
+}
+
+type columnConfig struct {
+	Cardinality string
+	// NullRate is nil when it is not set, to tell it from 0
+	NullRate *float64
+	Length   string
+	// Weights is the rates of the members of ENUM and SET, e.g. published: 0.9
+	Weights     map[string]float64
+	BoundingBox string
+}
+
+func loadConfig() (config, error) {
+	var c config
+	if err := viper.Unmarshal(&c); err != nil {
+		return config{}, errors.Wrap(err, "cannot read the config")
+	}
+	return c, nil
+}
+
+// loadRecordNumber builds model.RecordNumber from the config file and the flags.
+// the tables given in the recordNumber key (e.g. -n orders=100) take priority over tables.<name>.recordNumber.
+func loadRecordNumber(c config) (model.RecordNumber, error) {
+	rn, err := model.ParseRecordNumber(c.RecordNumber, defaultRecordNumber)
+	if err != nil {
+		return model.RecordNumber{}, err
+	}
+	for tn, tc := range c.Tables {
+		if tc.RecordNumber == 0 {
+			continue
+		}
+		if tc.RecordNumber < 0 {
+			return model.RecordNumber{}, errors.Errorf("invalid record number %d for table %s, it must be a positive number", tc.RecordNumber, tn)
+		}
+		if _, ok := rn.Tables[model.TableName(tn)]; !ok {
+			rn.Tables[model.TableName(tn)] = tc.RecordNumber
+		}
+	}
+	return rn, nil
+}
+
+// loadOption builds model.Option from the config file and the flags.
+func loadOption(c config) (model.Option, error) {
+	opt := model.NewOption()
+	if c.Cardinality != "" {
+		cardinality, err := model.ParseCardinality(c.Cardinality)
+		if err != nil {
+			return model.Option{}, err
+		}
+		opt.Cardinality = cardinality
+	}
+	if err := validateRate(c.NullRate); err != nil {
+		return model.Option{}, errors.Wrap(err, "invalid null rate")
+	}
+	opt.NullRate = c.NullRate
+	if c.Length != "" {
+		length, err := model.ParseLengthRange(c.Length)
+		if err != nil {
+			return model.Option{}, err
+		}
+		opt.Length = length
+	}
+	if err := validateRate(c.DefaultRate); err != nil {
+		return model.Option{}, errors.Wrap(err, "invalid default rate")
+	}
+	opt.OmitDefaults = c.OmitDefaults
+	opt.DefaultRate = c.DefaultRate
+	if err := validateRate(c.SpecialFloatRate); err != nil {
+		return model.Option{}, errors.Wrap(err, "invalid special float rate")
+	}
+	opt.SpecialFloatRate = c.SpecialFloatRate
+	if c.SRID < 0 {
+		return model.Option{}, errors.Errorf("invalid SRID %d, it must not be negative", c.SRID)
+	}
+	opt.SRID = c.SRID
+	if c.BoundingBox != "" {
+		bb, err := model.ParseBoundingBox(c.BoundingBox)
+		if err != nil {
+			return model.Option{}, err
+		}
+		opt.BoundingBox = bb
+	}
+	if c.Dialect != "" {
+		dialect, err := model.ParseDialect(c.Dialect)
+		if err != nil {
+			return model.Option{}, err
+		}
+		opt.Dialect = dialect
+	}
+	if c.Format != "" {
+		format, err := model.ParseFormat(c.Format)
+		if err != nil {
+			return model.Option{}, err
+		}
+		opt.Format = format
+	}
+	if err := opt.Validate(); err != nil {
+		return model.Option{}, err
+	}
+	if c.BatchSize < 0 {
+		return model.Option{}, errors.Errorf("invalid batch size %d, it must not be negative", c.BatchSize)
+	}
+	if c.MaxStatementBytes < 0 {
+		return model.Option{}, errors.Errorf("invalid max statement bytes %d, it must not be negative", c.MaxStatementBytes)
+	}
+	opt.BatchSize = c.BatchSize
+	opt.MaxStatementBytes = c.MaxStatementBytes
+	if c.TableOrder != "" {
+		order, err := model.ParseTableOrder(c.TableOrder)
+		if err != nil {
+			return model.Option{}, err
+		}
+		opt.TableOrder = order
+	}
+	if c.BreakCycles && opt.TableOrder != model.DependencyOrder {
+		return model.Option{}, errors.New("breakCycles is available only for dependency table order")
+	}
+	if c.BreakCycles && opt.Format != model.SQL {
+		return model.Option{}, errors.Errorf("breakCycles is available only for sql format, because %s cannot express UPDATE", opt.Format)
+	}
+	opt.BreakCycles = c.BreakCycles
+	if c.HierarchyDepth < 1 {
+		return model.Option{}, errors.Errorf("invalid hierarchy depth %d, it must be a positive number", c.HierarchyDepth)
+	}
+	opt.HierarchyDepth = c.HierarchyDepth
+	// without the seed, every run gives different data
+	opt.Seed = c.Seed
+	if !viper.IsSet("seed") {
+		opt.Seed = time.Now().UnixNano()
+	}
+	for tn, tc := range c.Tables {
+		for cn, cc := range tc.Columns {
+			fn := model.NewColumnFullName(model.TableName(tn), model.ColumnName(cn))
+			if cc.Cardinality != "" {
+				cardinality, err := model.ParseCardinality(cc.Cardinality)
+				if err != nil {
+					return model.Option{}, errors.Wrapf(err, "invalid setting for %s", fn)
+				}
+				opt.ColumnCardinalities[fn] = cardinality
+			}
+			if cc.NullRate != nil {
+				if err := validateRate(*cc.NullRate); err != nil {
+					return model.Option{}, errors.Wrapf(err, "invalid null rate for %s", fn)
+				}
+				opt.ColumnNullRates[fn] = *cc.NullRate
+			}
+			if cc.Length != "" {
+				length, err := model.ParseLengthRange(cc.Length)
+				if err != nil {
+					return model.Option{}, errors.Wrapf(err, "invalid setting for %s", fn)
+				}
+				opt.ColumnLengths[fn] = length
+			}
+			if len(cc.Weights) > 0 {
+				opt.ColumnWeights[fn] = cc.Weights
+			}
+			if cc.BoundingBox != "" {
+				bb, err := model.ParseBoundingBox(cc.BoundingBox)
+				if err != nil {
+					return model.Option{}, errors.Wrapf(err, "invalid setting for %s", fn)
+				}
+				opt.ColumnBoundingBoxes[fn] = bb
+			}
+		}
+	}
+	return opt, nil
+}
+
+func validateRate(r float64) error {
+	if r < 0 || r > 1 {
+		return errors.Errorf("%v is out of range, it must be from 0 to 1", r)
+	}
+	return nil
+}
@@ -3,7 +3,9 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strconv"
 
+	"github.com/canalun/sqloth/domain/model"
 	"github.com/canalun/sqloth/driver/file_driver"
 	"github.com/canalun/sqloth/usecase"
 	"github.com/spf13/cobra"
@@ -18,18 +20,32 @@ var rootCmd = &cobra.Command{
 	Use:   "sqloth",
 	Short: "generate dummy data for given schema",
 	Long:  ``,
+	// errors are printed once by Execute, without usage
+	SilenceErrors: true,
+	SilenceUsage:  true,
 	// TODO: good variable name
-	Run: func(cmd *cobra.Command, args []string) {
+	RunE: func(cmd *cobra.Command, args []string) error {
 		fp, _ := cmd.Flags().GetString("filePath")
-		num, _ := cmd.Flags().GetInt("recordNumber")
+
+		c, err := loadConfig()
+		if err != nil {
+			return err
+		}
+		rn, err := loadRecordNumber(c)
+		if err != nil {
+			return err
+		}
+		opt, err := loadOption(c)
+		if err != nil {
+			return err
+		}
+		// the seed is shown so that the same data can be generated again with --seed
+		fmt.Fprintln(os.Stderr, "Using seed:", opt.Seed)
 
 		fd := file_driver.NewFileDriver(fp)
 		u := usecase.NewUsecase(fd)
 
-		queries := u.GenerateQueryOfDummyData(num)
-		for _, query := range queries {
-			fmt.Printf("%s\n\n", query)
-		}
+		return u.GenerateDummyData(os.Stdout, rn, opt)
 	},
 }
 
@@ -50,8 +66,41 @@ func init() {
 
 	// Cobra also supports local flags, which will only run
 	// when this action is called directly.
-	rootCmd.Flags().IntP("recordNumber", "n", 10, "the # of records you want")
+	rootCmd.Flags().StringP("recordNumber", "n", strconv.Itoa(defaultRecordNumber), "the # of records you want, for all tables (e.g. 10) and/or for each table (e.g. orders=100000,country=50)")
+	cobra.CheckErr(viper.BindPFlag("recordNumber", rootCmd.Flags().Lookup("recordNumber")))
 	rootCmd.Flags().StringP("filePath", "f", "./dump.sql", "the path to the schema sql file")
+	rootCmd.Flags().String("cardinality", string(model.Uniform), "how child rows refer to parent rows of foreign keys: uniform, skewed or exact:<k>")
+	cobra.CheckErr(viper.BindPFlag("cardinality", rootCmd.Flags().Lookup("cardinality")))
+	rootCmd.Flags().Float64("nullRate", 0, "the rate of NULL in nullable columns, from 0 to 1")
+	cobra.CheckErr(viper.BindPFlag("nullRate", rootCmd.Flags().Lookup("nullRate")))
+	rootCmd.Flags().String("length", "", "the range of the lengths of strings and binary data, e.g. 1-20 or 8 (default is the length of the type, or 100 for TEXT and BLOB)")
+	cobra.CheckErr(viper.BindPFlag("length", rootCmd.Flags().Lookup("length")))
+	rootCmd.Flags().Bool("omitDefaults", false, "leave the columns with DEFAULT clauses out of INSERT statements")
+	cobra.CheckErr(viper.BindPFlag("omitDefaults", rootCmd.Flags().Lookup("omitDefaults")))
+	rootCmd.Flags().Float64("defaultRate", 0, "the rate of DEFAULT in the columns with DEFAULT clauses, from 0 to 1")
+	cobra.CheckErr(viper.BindPFlag("defaultRate", rootCmd.Flags().Lookup("defaultRate")))
+	rootCmd.Flags().Float64("specialFloatRate", 0, "the rate of 0, -0 and the min and the max magnitude in FLOAT and DOUBLE columns, from 0 to 1")
+	cobra.CheckErr(viper.BindPFlag("specialFloatRate", rootCmd.Flags().Lookup("specialFloatRate")))
+	rootCmd.Flags().Int("srid", 0, "the SRID of the values of the spatial columns without SRID attributes, e.g. 4326 (default is no SRID)")
+	cobra.CheckErr(viper.BindPFlag("srid", rootCmd.Flags().Lookup("srid")))
+	rootCmd.Flags().String("boundingBox", "", "the area of the values of spatial columns in min x, min y, max x and max y, e.g. 139.56,35.53,139.92,35.82 for the Tokyo area (default is the whole world)")
+	cobra.CheckErr(viper.BindPFlag("boundingBox", rootCmd.Flags().Lookup("boundingBox")))
+	rootCmd.Flags().Int64("seed", 0, "the seed of random data. the same seed, schema and options generate the same data (default is random)")
+	cobra.CheckErr(viper.BindPFlag("seed", rootCmd.Flags().Lookup("seed")))
+	rootCmd.Flags().String("dialect", string(model.DefaultDialect), "the SQL dialect of the queries: mysql or ansi")
+	cobra.CheckErr(viper.BindPFlag("dialect", rootCmd.Flags().Lookup("dialect")))
+	rootCmd.Flags().String("format", string(model.DefaultFormat), "the output format: sql, csv or json")
+	cobra.CheckErr(viper.BindPFlag("format", rootCmd.Flags().Lookup("format")))
+	rootCmd.Flags().Int("batchSize", 0, "the max # of rows in an INSERT statement (default is no limit)")
+	cobra.CheckErr(viper.BindPFlag("batchSize", rootCmd.Flags().Lookup("batchSize")))
+	rootCmd.Flags().Int("maxStatementBytes", 0, "the max size of an INSERT statement in bytes, e.g. max_allowed_packet of MySQL (default is no limit)")
+	cobra.CheckErr(viper.BindPFlag("maxStatementBytes", rootCmd.Flags().Lookup("maxStatementBytes")))
+	rootCmd.Flags().String("tableOrder", string(model.DefaultTableOrder), "the order of the tables: file, disabling foreign key checks of MySQL, or dependency, putting referred tables first")
+	cobra.CheckErr(viper.BindPFlag("tableOrder", rootCmd.Flags().Lookup("tableOrder")))
+	rootCmd.Flags().Bool("breakCycles", false, "break the cycles of foreign keys in dependency order, by inserting NULL and updating the values afterwards")
+	cobra.CheckErr(viper.BindPFlag("breakCycles", rootCmd.Flags().Lookup("breakCycles")))
+	rootCmd.Flags().Int("hierarchyDepth", model.DefaultHierarchyDepth, "the # of the levels of the trees made by self-referencing foreign keys, e.g. employee.manager_id")
+	cobra.CheckErr(viper.BindPFlag("hierarchyDepth", rootCmd.Flags().Lookup("hierarchyDepth")))
 }
 
 // initConfig reads in config file and ENV variables if set.
@@ -3,5 +3,5 @@ package driver
 import "github.com/canalun/sqloth/domain/model"
 
 type Driver interface {
-	GetSchema() model.Schema
+	GetSchema() (model.Schema, error)
 }
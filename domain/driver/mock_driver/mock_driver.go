@@ -35,11 +35,12 @@ func (m *MockDriver) EXPECT() *MockDriverMockRecorder {
 }
 
 // GetSchema mocks base method.
-func (m *MockDriver) GetSchema() model.Schema {
+func (m *MockDriver) GetSchema() (model.Schema, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSchema")
 	ret0, _ := ret[0].(model.Schema)
-	return ret0
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // GetSchema indicates an expected call of GetSchema.
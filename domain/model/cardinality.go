@@ -0,0 +1,66 @@
+package model
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// CardinalityKind is the way to decide which parent row each child row of a foreign key refers to
+type CardinalityKind string
+
+const (
+	// every parent row is equally likely to be referred to
+	Uniform CardinalityKind = "uniform"
+	// a small part of parent rows is referred to by most of the child rows, roughly 80% of children to 20% of parents
+	Skewed CardinalityKind = "skewed"
+	// each parent row is referred to by exactly K child rows, in order
+	Exact CardinalityKind = "exact"
+)
+
+// skewExponent makes 20% of parent rows be chosen by about 80% of child rows; 0.2^(1/7) ≒ 0.8
+const skewExponent = 7
+
+type Cardinality struct {
+	Kind CardinalityKind
+	// K is the number of children per parent, used only when Kind is Exact
+	K int
+}
+
+var DefaultCardinality = Cardinality{Kind: Uniform}
+
+// ParseCardinality converts strings such as "uniform", "skewed" and "exact:3" into Cardinality.
+func ParseCardinality(str string) (Cardinality, error) {
+	kind, k, hasK := strings.Cut(strings.ToLower(strings.TrimSpace(str)), ":")
+	switch CardinalityKind(kind) {
+	case Uniform, Skewed:
+		if hasK {
+			return Cardinality{}, errors.Errorf("cardinality %q does not take the number of children", str)
+		}
+		return Cardinality{Kind: CardinalityKind(kind)}, nil
+	case Exact:
+		n, err := strconv.Atoi(k)
+		if err != nil || n < 1 {
+			return Cardinality{}, errors.Errorf("cardinality %q needs a positive number of children, e.g. exact:3", str)
+		}
+		return Cardinality{Kind: Exact, K: n}, nil
+	default:
+		return Cardinality{}, errors.Errorf("unknown cardinality %q, it must be uniform, skewed or exact:<k>", str)
+	}
+}
+
+// ParentIndex returns the index of the parent row which the i-th child row refers to, out of n parent rows.
+// with Exact, the children beyond n*K refer to the parents from the beginning again.
+func (c Cardinality) ParentIndex(r *rand.Rand, i, n int) int {
+	switch c.Kind {
+	case Skewed:
+		return int(float64(n) * math.Pow(r.Float64(), skewExponent))
+	case Exact:
+		return (i / c.K) % n
+	default:
+		return r.Intn(n)
+	}
+}
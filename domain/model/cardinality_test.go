@@ -0,0 +1,131 @@
+package model
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseCardinality(t *testing.T) {
+	type args struct {
+		str string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Cardinality
+		wantErr bool
+	}{
+		{
+			name: "parse uniform",
+			args: args{str: "uniform"},
+			want: Cardinality{Kind: Uniform},
+		},
+		{
+			name: "parse skewed case-insensitively",
+			args: args{str: " Skewed "},
+			want: Cardinality{Kind: Skewed},
+		},
+		{
+			name: "parse exact with the number of children",
+			args: args{str: "exact:3"},
+			want: Cardinality{Kind: Exact, K: 3},
+		},
+		{
+			name:    "return error for exact without the number of children",
+			args:    args{str: "exact"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for exact with non-positive number",
+			args:    args{str: "exact:0"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for uniform with the number of children",
+			args:    args{str: "uniform:2"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for unknown cardinality",
+			args:    args{str: "random"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseCardinality(tt.args.str)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseCardinality() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("ParseCardinality(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestCardinality_ParentIndex(t *testing.T) {
+	type args struct {
+		children int
+		parents  int
+	}
+	tests := []struct {
+		name        string
+		cardinality Cardinality
+		args        args
+		assertFn    func(counts []int)
+	}{
+		{
+			name:        "exact gives each parent k children in order",
+			cardinality: Cardinality{Kind: Exact, K: 3},
+			args:        args{children: 12, parents: 4},
+			assertFn: func(counts []int) {
+				diff := cmp.Diff(counts, []int{3, 3, 3, 3})
+				if diff != "" {
+					t.Errorf("-got, +want\n%v", diff)
+				}
+			},
+		},
+		{
+			name:        "uniform refers to every parent",
+			cardinality: Cardinality{Kind: Uniform},
+			args:        args{children: 10000, parents: 10},
+			assertFn: func(counts []int) {
+				for i, c := range counts {
+					if c == 0 {
+						t.Errorf("parent %v is never referred to", i)
+					}
+				}
+			},
+		},
+		{
+			name:        "skewed refers to the first 20% of parents much more than to the others",
+			cardinality: Cardinality{Kind: Skewed},
+			args:        args{children: 10000, parents: 10},
+			assertFn: func(counts []int) {
+				head := counts[0] + counts[1]
+				if head < 7000 {
+					t.Errorf("the first 20%% of parents have only %v children out of 10000", head)
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			counts := make([]int, tt.args.parents)
+			for i := 0; i < tt.args.children; i++ {
+				idx := tt.cardinality.ParentIndex(r, i, tt.args.parents)
+				if idx < 0 || tt.args.parents <= idx {
+					t.Fatalf("Cardinality.ParentIndex() = %v, out of range", idx)
+				}
+				counts[idx]++
+			}
+			tt.assertFn(counts)
+		})
+	}
+}
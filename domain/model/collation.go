@@ -0,0 +1,43 @@
+package model
+
+import "strings"
+
+// isText tells whether the type is one of the string types, which have collations.
+func isText(t ColumnTypeBase) bool {
+	switch t {
+	case Varchar, Char, Tinytext, Text, Mediumtext, Longtext, Enum, Set:
+		return true
+	default:
+		return false
+	}
+}
+
+// caseInsensitive tells whether the values of the column are compared case-insensitively in keys,
+// which is true for the string types of the collations other than binary, _bin and _cs ones, e.g. utf8mb4_0900_ai_ci.
+// the members of ENUM and SET are distinct by the definition, so they are compared as they are.
+func (c Column) caseInsensitive() bool {
+	if !isText(c.Type.Base) || c.Type.Base == Enum || c.Type.Base == Set {
+		return false
+	}
+	collation := strings.ToLower(c.Collation)
+	return collation != "binary" && !strings.HasSuffix(collation, "_bin") && !strings.HasSuffix(collation, "_cs")
+}
+
+// uniqueValue returns the value as it is compared in keys of the column, e.g. abc for ABC in a case-insensitive column.
+func (c Column) uniqueValue(v Value) Value {
+	if v.Kind != StringKind || !c.caseInsensitive() {
+		return v
+	}
+	return Value{Kind: v.Kind, Data: ColumnData(strings.ToLower(string(v.Data)))}
+}
+
+// uniqueKey is tupleKey of the values as they are compared in keys, where columns are the columns of the record.
+func uniqueKey(record Record, indexes []int, columns []Column) (key string, hasNull bool) {
+	values := make(Record, 0, len(indexes))
+	positions := make([]int, 0, len(indexes))
+	for k, j := range indexes {
+		values = append(values, columns[j].uniqueValue(record[j]))
+		positions = append(positions, k)
+	}
+	return tupleKey(values, positions)
+}
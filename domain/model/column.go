@@ -1,18 +1,28 @@
 package model
 
 import (
-	"errors"
+	"math/rand"
 	"strings"
+
+	"github.com/pkg/errors"
 )
 
 // TODO: reorder and reorganize source code
 
+var ErrUnregisteredType = errors.New("unregistered type")
+
 type ColumnTypeBase string
 type ColumnTypeParam int
 
+// ColumnType is a data type of columns.
+// Param is the first parameter of the type, e.g. 255 of varchar(255) and the precision 10 of decimal(10,2),
+// and Scale is the # of digits after the decimal point, e.g. 2 of decimal(10,2).
+// Members is the allowed values of ENUM and SET, e.g. draft, published and archived of enum('draft','published','archived').
 type ColumnType struct {
-	Base  ColumnTypeBase
-	Param ColumnTypeParam
+	Base    ColumnTypeBase
+	Param   ColumnTypeParam
+	Scale   ColumnTypeParam
+	Members []string
 }
 
 const (
@@ -20,6 +30,14 @@ const (
 	Varbinary  ColumnTypeBase = "varbinary"
 	Mediumblob ColumnTypeBase = "mediumblob"
 	Text       ColumnTypeBase = "text"
+	Char       ColumnTypeBase = "char"
+	Binary     ColumnTypeBase = "binary"
+	Tinytext   ColumnTypeBase = "tinytext"
+	Mediumtext ColumnTypeBase = "mediumtext"
+	Longtext   ColumnTypeBase = "longtext"
+	Tinyblob   ColumnTypeBase = "tinyblob"
+	Blob       ColumnTypeBase = "blob"
+	Longblob   ColumnTypeBase = "longblob"
 	Tinyint    ColumnTypeBase = "tinyint"
 	Smallint   ColumnTypeBase = "smallint"
 	Mediumint  ColumnTypeBase = "mediumint"
@@ -27,9 +45,54 @@ const (
 	Bigint     ColumnTypeBase = "bigint"
 	Timestamp  ColumnTypeBase = "timestamp"
 	Datetime   ColumnTypeBase = "datetime"
+	Date       ColumnTypeBase = "date"
+	Time       ColumnTypeBase = "time"
+	Year       ColumnTypeBase = "year"
 	Json       ColumnTypeBase = "json"
+	Decimal    ColumnTypeBase = "decimal"
+	Float      ColumnTypeBase = "float"
+	Double     ColumnTypeBase = "double"
+	Bit        ColumnTypeBase = "bit"
+	Enum       ColumnTypeBase = "enum"
+	Set        ColumnTypeBase = "set"
+	// the spatial types, whose values are written by ST_GeomFromText
+	Geometry           ColumnTypeBase = "geometry"
+	Point              ColumnTypeBase = "point"
+	Linestring         ColumnTypeBase = "linestring"
+	Polygon            ColumnTypeBase = "polygon"
+	Multipoint         ColumnTypeBase = "multipoint"
+	Multilinestring    ColumnTypeBase = "multilinestring"
+	Multipolygon       ColumnTypeBase = "multipolygon"
+	Geometrycollection ColumnTypeBase = "geometrycollection"
+)
+
+// the precision and the scale of DECIMAL in MySQL
+const (
+	DefaultDecimalPrecision = 10
+	MaxDecimalPrecision     = 65
+	MaxDecimalScale         = 30
+)
+
+// the precision of FLOAT(p) in MySQL, which is DOUBLE for p over MaxFloatPrecision,
+// and the max # of digits of FLOAT(M,D) and DOUBLE(M,D)
+const (
+	MaxFloatPrecision  = 24
+	MaxDoublePrecision = 53
+	MaxFloatDigits     = 255
+)
+
+// the max # of the members of ENUM and SET in MySQL
+const (
+	MaxEnumMembers = 65535
+	MaxSetMembers  = 64
 )
 
+// MaxBitWidth is the max # of bits of BIT in MySQL
+const MaxBitWidth = 64
+
+// MaxFsp is the max fractional seconds precision of DATETIME, TIMESTAMP and TIME in MySQL, e.g. DATETIME(6)
+const MaxFsp = 6
+
 func StrToColumnTypeBase(str string) (ColumnTypeBase, error) {
 	switch str {
 	case string(Varchar):
@@ -40,18 +103,74 @@ func StrToColumnTypeBase(str string) (ColumnTypeBase, error) {
 		return Mediumblob, nil
 	case string(Text):
 		return Text, nil
-	case string(Smallint), string(Int), string(Mediumint), string(Bigint):
+	case string(Char), "character":
+		return Char, nil
+	case string(Binary):
+		return Binary, nil
+	case string(Tinytext):
+		return Tinytext, nil
+	case string(Mediumtext):
+		return Mediumtext, nil
+	case string(Longtext):
+		return Longtext, nil
+	case string(Tinyblob):
+		return Tinyblob, nil
+	case string(Blob):
+		return Blob, nil
+	case string(Longblob):
+		return Longblob, nil
+	case string(Smallint):
+		return Smallint, nil
+	case string(Mediumint), "middleint":
+		return Mediumint, nil
+	case string(Int), "integer":
 		return Int, nil
-	case string(Tinyint):
+	case string(Bigint):
+		return Bigint, nil
+	case string(Tinyint), "bool", "boolean":
 		return Tinyint, nil
+	case string(Bit):
+		return Bit, nil
 	case string(Timestamp):
 		return Timestamp, nil
 	case string(Datetime):
 		return Datetime, nil
+	case string(Date):
+		return Date, nil
+	case string(Time):
+		return Time, nil
+	case string(Year):
+		return Year, nil
 	case string(Json):
 		return Json, nil
+	case string(Decimal), "numeric", "dec", "fixed":
+		return Decimal, nil
+	case string(Float):
+		return Float, nil
+	case string(Double), "real":
+		return Double, nil
+	case string(Enum):
+		return Enum, nil
+	case string(Set):
+		return Set, nil
+	case string(Geometry):
+		return Geometry, nil
+	case string(Point):
+		return Point, nil
+	case string(Linestring):
+		return Linestring, nil
+	case string(Polygon):
+		return Polygon, nil
+	case string(Multipoint):
+		return Multipoint, nil
+	case string(Multilinestring):
+		return Multilinestring, nil
+	case string(Multipolygon):
+		return Multipolygon, nil
+	case string(Geometrycollection), "geomcollection":
+		return Geometrycollection, nil
 	default:
-		return "", errors.New("unregistered type")
+		return "", ErrUnregisteredType
 	}
 }
 
@@ -59,9 +178,15 @@ func StrToColumnTypeBase(str string) (ColumnTypeBase, error) {
 type ColumnData string
 type ColumnName string
 type ColumnFullName string
+
+// Constraint is a foreign key of a column, which refers to ColumnName of TableName.
 type Constraint struct {
 	TableName
 	ColumnName
+	// Columns and ReferencedColumns are all the columns of a composite foreign key, in the order of the definition.
+	// every column of the key has the same Columns and ReferencedColumns, and they are empty for a foreign key of a single column.
+	Columns           []ColumnName
+	ReferencedColumns []ColumnName
 }
 
 func NewConstraint(tn TableName, cn ColumnName) Constraint {
@@ -71,17 +196,70 @@ func NewConstraint(tn TableName, cn ColumnName) Constraint {
 	}
 }
 
+// NewForeignKeyConstraints returns the constraints of the columns of a foreign key, in the order of the columns.
+func NewForeignKeyConstraints(tn TableName, columns, referencedColumns []ColumnName) []Constraint {
+	constraints := make([]Constraint, 0, len(columns))
+	for _, cn := range referencedColumns {
+		constraint := NewConstraint(tn, cn)
+		if len(columns) > 1 {
+			constraint.Columns = columns
+			constraint.ReferencedColumns = referencedColumns
+		}
+		constraints = append(constraints, constraint)
+	}
+	return constraints
+}
+
+// IsComposite tells whether the foreign key has more than one column.
+func (c Constraint) IsComposite() bool {
+	return len(c.Columns) > 1
+}
+
+// referencedColumns returns all the columns referred to by the foreign key.
+func (c Constraint) referencedColumns() []ColumnName {
+	if c.IsComposite() {
+		return c.ReferencedColumns
+	}
+	return []ColumnName{c.ColumnName}
+}
+
+// key identifies the foreign key among the constraints of the columns of a table.
+func (c Constraint) key() string {
+	return string(c.TableName) + "(" + joinKey(c.Columns) + ")(" + joinKey(c.ReferencedColumns) + ")"
+}
+
 type Column struct {
 	Name          ColumnName
 	FullName      ColumnFullName
 	Type          ColumnType
 	AutoIncrement bool
-	Unsigned      bool
-	Constraints   []Constraint
+	// AutoIncrementStart is the value of the first row of the auto increment column, set by the AUTO_INCREMENT table option.
+	// 0 means 1, which is the first value for a new table
+	AutoIncrementStart int64
+	// Generated is true for generated columns (GENERATED ALWAYS AS), whose values are computed by the database
+	Generated bool
+	Unsigned  bool
+	// NotNull is true for NOT NULL columns and primary key columns
+	NotNull bool
+	// Collation is the collation of a string column, e.g. utf8mb4_bin, or binary for the BINARY attribute and CHARACTER SET binary.
+	// it is empty for the default collation, which is case-insensitive
+	Collation string
+	// Default is the expression of the DEFAULT clause, e.g. 'active', CURRENT_TIMESTAMP, NULL
+	Default     string
+	HasDefault  bool
+	Constraints []Constraint
+	// Length is the range of the lengths of the values of string and binary columns, set by Option.LengthOf before the generation
+	Length LengthRange
+	// Weights is the rates of the members of ENUM and SET columns, set by Option.WeightsOf before the generation, see generateRandomEnum
+	Weights map[string]float64
+	// SRID is the spatial reference system of spatial columns, e.g. 4326 of SRID 4326. Option.SRID is used when it is 0
+	SRID int
+	// BoundingBox is the area of the values of spatial columns, set by Option.BoundingBoxOf before the generation
+	BoundingBox BoundingBox
 }
 
 func NewColumn(fullName ColumnFullName, ct ColumnType) Column {
-	name := strings.Split(string(fullName), ".")[1]
+	_, name, _ := strings.Cut(string(fullName), ".")
 	return Column{
 		Name:     ColumnName(name),
 		FullName: fullName,
@@ -93,14 +271,59 @@ func NewColumnFullName(tn TableName, cn ColumnName) ColumnFullName {
 	return ColumnFullName(string(tn) + "." + string(cn))
 }
 
+// TableName returns the name of the table which the column belongs to.
+func (fn ColumnFullName) TableName() TableName {
+	tn, _, _ := strings.Cut(string(fn), ".")
+	return TableName(tn)
+}
+
 func (c *Column) SetAutoIncrement() {
 	c.AutoIncrement = true
 }
 
+// autoIncrementValue returns the value which the database gives to the i-th row of the auto increment column of an empty table.
+func (c Column) autoIncrementValue(i int) Value {
+	start := c.AutoIncrementStart
+	if start < 1 {
+		start = 1
+	}
+	return NewIntValue(start + int64(i))
+}
+
+func (c *Column) SetCollation(collation string) {
+	c.Collation = collation
+}
+
+func (c *Column) SetGenerated() {
+	c.Generated = true
+}
+
 func (c *Column) SetUnsigned(b bool) {
 	c.Unsigned = b
 }
 
+func (c *Column) SetSRID(srid int) {
+	c.SRID = srid
+}
+
+func (c *Column) SetNotNull() {
+	c.NotNull = true
+}
+
+func (c *Column) SetDefault(expr string) {
+	c.Default = expr
+	c.HasDefault = true
+}
+
+// nullRate returns the rate of NULL in the values of the column.
+// NOT NULL columns never get NULL, and auto increment columns are always NULL to be given values by the database.
+func (c Column) nullRate(opt Option) float64 {
+	if c.NotNull || c.AutoIncrement {
+		return 0
+	}
+	return opt.NullRateOf(c.FullName)
+}
+
 func (c Column) HasConstraint() bool {
 	return len(c.Constraints) > 0
 }
@@ -109,104 +332,462 @@ func (c *Column) SetConstraint(constraint Constraint) {
 	c.Constraints = append(c.Constraints, constraint)
 }
 
-func (c Column) GenerateData(n int) []Value {
+// compositeConstraint returns the first composite foreign key of the column.
+// the column takes its values from the same parent rows as the other columns of the key, see parentTuples.
+func (c Column) compositeConstraint() (Constraint, bool) {
+	for _, constraint := range c.Constraints {
+		if constraint.IsComposite() {
+			return constraint, true
+		}
+	}
+	return Constraint{}, false
+}
+
+// GenerateData returns n values, each of which is NULL at the rate of nullRate.
+func (c Column) GenerateData(r *rand.Rand, n int, nullRate float64) []Value {
 	d := []Value{}
 	switch c.AutoIncrement {
 	case true:
 		for i := 0; i < n; i++ {
-			d = append(d, Value("NULL"))
+			d = append(d, NullValue)
 		}
 	default:
 		for i := 0; i < n; i++ {
-			d = append(d, Value(c.GenerateRandomData()))
+			if isNull(r, nullRate) {
+				d = append(d, NullValue)
+				continue
+			}
+			d = append(d, c.GenerateRandomData(r))
 		}
 	}
 	return d
 }
 
-func (c Column) GenerateRandomData() ColumnData {
-	var data string
+// GenerateUniqueData returns n values which are distinct except NULL, each of which is NULL at the rate of nullRate.
+// it returns error if the type of the column cannot have enough distinct values.
+func (c Column) GenerateUniqueData(r *rand.Rand, n int, nullRate float64) ([]Value, error) {
+	if c.AutoIncrement {
+		return c.GenerateData(r, n, 0), nil
+	}
+	d := make([]Value, n)
+	m := 0
+	for i := range d {
+		if isNull(r, nullRate) {
+			d[i] = NullValue
+		} else {
+			m++
+		}
+	}
+	if size := c.domainSize(); size < uint64(m) {
+		return nil, errors.Errorf("cannot generate %d unique values for %s, because %s can have only %d distinct values", m, c.FullName, c.Type.Base, size)
+	}
+	seen := make(map[Value]bool, m)
+	for i := range d {
+		if d[i].IsNull() {
+			continue
+		}
+		for {
+			v := c.GenerateRandomData(r)
+			if u := c.uniqueValue(v); !seen[u] {
+				seen[u] = true
+				d[i] = v
+				break
+			}
+		}
+	}
+	return d, nil
+}
+
+func isNull(r *rand.Rand, nullRate float64) bool {
+	return nullRate > 0 && r.Float64() < nullRate
+}
+
+// GenerateRandomData returns a random value of the type of the column.
+func (c Column) GenerateRandomData(r *rand.Rand) Value {
 	switch c.Type.Base {
-	case Varchar, Text, Varbinary, Mediumblob:
-		data = generateRandomString(int(c.Type.Param))
-	case Int:
-		data = generateRandomInt(c.Type.Base, c.Unsigned)
+	case Varchar, Char, Tinytext, Text, Mediumtext, Longtext:
+		return generateRandomString(r, c.randomLength(r))
+	case Varbinary, Binary, Tinyblob, Blob, Mediumblob, Longblob:
+		return generateRandomBytes(r, c.randomLength(r))
+	case Smallint, Mediumint, Int, Bigint:
+		return generateRandomInt(r, c.Type.Base, c.Unsigned)
 	case Tinyint:
-		data = generateRandomTinyint()
-	case Timestamp, Datetime:
-		data = generateRandomDate()
+		return generateRandomTinyint(r, int(c.Type.Param), c.Unsigned)
+	case Bit:
+		return generateRandomBit(r, int(c.Type.Param))
+	case Timestamp, Datetime, Date:
+		return generateRandomDate(r, c.Type.Base, int(c.Type.Param))
+	case Time:
+		return generateRandomTime(r, int(c.Type.Param))
+	case Year:
+		return generateRandomYear(r)
 	case Json:
-		data = generateRandomJson()
+		return generateRandomJson(r)
+	case Decimal:
+		return generateRandomDecimal(r, int(c.Type.Param), int(c.Type.Scale), c.Unsigned)
+	case Float, Double:
+		return generateRandomFloat(r, c.Type, c.Unsigned)
+	case Enum:
+		return generateRandomEnum(r, c.Type.Members, c.Weights)
+	case Set:
+		return generateRandomSet(r, c.Type.Members, c.Weights)
+	case Geometry, Point, Linestring, Polygon, Multipoint, Multilinestring, Multipolygon, Geometrycollection:
+		return generateRandomGeometry(r, c.Type.Base, c.SRID, c.BoundingBox)
+	default:
+		return NewStringValue("")
 	}
-	return ColumnData(data)
 }
 
+// GenerateValuesForColumns generates the values of all the columns with r, so that the same seed of r gives the same values.
+// the columns are generated in a topological order of the graph, parents before children,
+// where the columns on a cycle of foreign keys are generated together, see generateValuesForCycle.
 //TODO: better to be defined as a method of map[ColumnFullName][]Value?
-//TODO: shuffle values. currently, values with constraints are just simple sum of strings in the order.
-func GenerateValuesForColumns(cg ColumnGraph, n int) map[ColumnFullName][]Value {
+func GenerateValuesForColumns(r *rand.Rand, cg ColumnGraph, rn RecordNumber, opt Option) (map[ColumnFullName][]Value, error) {
 	dict := map[ColumnFullName][]Value{}
-	for i := range cg.ColumnNodes {
-		if !cg.isAllDone() {
-			generateValuesForColumnsByRecursion(&cg, i, n, dict)
+	for _, key := range cg.keys {
+		if err := generateKeyValues(r, &cg, key, rn, opt, dict); err != nil {
+			return nil, err
+		}
+	}
+	for _, group := range generationOrder(cg) {
+		if len(group) > 1 || containsIndex(cg.AdjacencyList[group[0]], group[0]) {
+			if err := generateValuesForCycle(r, &cg, group, rn, opt, dict); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		// the columns of a composite key may be generated together in advance, see generateKeyValues
+		if cg.ColumnNodes[group[0]].IsDone() {
+			continue
+		}
+		if err := generateValuesForColumn(r, &cg, group[0], rn, opt, dict); err != nil {
+			return nil, err
+		}
+	}
+	return dict, nil
+}
+
+// generationOrder returns the groups of the nodes in the order of generation, each of which is a node or a cycle of nodes.
+// it is Kahn's algorithm over the cycles contracted into single groups, taking the groups ready in the order of the index,
+// so that no recursion is needed for a deep chain of foreign keys.
+func generationOrder(cg ColumnGraph) [][]int {
+	n := len(cg.AdjacencyList)
+	cycleOf := map[int][]int{}
+	for _, cycle := range cg.Cycles() {
+		for _, i := range cycle {
+			cycleOf[i] = cycle
+		}
+	}
+	groupOf := make([]int, n)
+	groups := [][]int{}
+	for i := 0; i < n; i++ {
+		cycle, ok := cycleOf[i]
+		switch {
+		case !ok:
+			groupOf[i] = len(groups)
+			groups = append(groups, []int{i})
+		case cycle[0] == i:
+			// the first node of a cycle decides the place of the cycle
+			for _, j := range cycle {
+				groupOf[j] = len(groups)
+			}
+			groups = append(groups, cycle)
+		}
+	}
+
+	pending := make([]int, len(groups))
+	for i, parents := range cg.AdjacencyList {
+		for _, p := range parents {
+			if groupOf[p] != groupOf[i] {
+				pending[groupOf[i]]++
+			}
 		}
 	}
-	return dict
+	children := cg.childrenLists()
+	order := make([][]int, 0, len(groups))
+	queue := []int{}
+	for g := range groups {
+		if pending[g] == 0 {
+			queue = append(queue, g)
+		}
+	}
+	for len(queue) > 0 {
+		g := queue[0]
+		queue = queue[1:]
+		order = append(order, groups[g])
+		for _, i := range groups[g] {
+			for _, c := range children[i] {
+				if h := groupOf[c]; h != g {
+					pending[h]--
+					if pending[h] == 0 {
+						queue = append(queue, h)
+					}
+				}
+			}
+		}
+	}
+	return order
 }
 
+// generateValuesForColumn generates the values of a column which is not on a cycle, whose parents are all generated.
 //TODO: better to be defined as a method with side-effect of map[ColumnFullName][]Value?
-func generateValuesForColumnsByRecursion(cg *ColumnGraph, i, n int, dict map[ColumnFullName][]Value) {
-	if cg.ColumnNodes[i].isDone {
-		return
+func generateValuesForColumn(r *rand.Rand, cg *ColumnGraph, i int, rn RecordNumber, opt Option, dict map[ColumnFullName][]Value) error {
+	c := cg.ColumnNodes[i].GetColumn()
+	n := rn.Of(c.FullName.TableName())
+	unique := cg.ColumnNodes[i].unique
+
+	parentNodeIndexes := cg.AdjacencyList[i]
+	var values []Value
+	var err error
+	switch {
+	case len(parentNodeIndexes) == 0:
+		values, err = generateRootValues(r, c, n, unique, opt)
+	default:
+		// a value can satisfy only one of the foreign keys in general, so the first parent is used
+		parent := cg.ColumnNodes[parentNodeIndexes[0]].GetColumn()
+		if constraint, ok := c.compositeConstraint(); ok {
+			values, err = generateTupleValues(r, cg, c, constraint, n, unique, opt, dict)
+		} else if isHierarchy(c, parent, unique) {
+			values = make([]Value, n)
+			for j := range values {
+				values[j] = hierarchyValue(r, c, parent, dict[parent.FullName], j, n, opt.hierarchyDepth())
+			}
+		} else {
+			parentValues := referableValues(parent, dict[parent.FullName])
+			values, err = referParentValues(r, c, parentValues, n, opt.CardinalityOf(c.FullName), unique, c.nullRate(opt))
+		}
+	}
+	if err != nil {
+		return err
+	}
+	dict[c.FullName] = values
+	cg.ColumnNodes[i].Done()
+	return nil
+}
+
+// generateRootValues generates the values of a column without parents.
+func generateRootValues(r *rand.Rand, c Column, n int, unique bool, opt Option) ([]Value, error) {
+	c, err := c.withOption(opt)
+	if err != nil {
+		return nil, err
 	}
+	if unique {
+		return c.GenerateUniqueData(r, n, c.nullRate(opt))
+	}
+	return c.GenerateData(r, n, c.nullRate(opt)), nil
+}
+
+// withOption returns the column with the settings of the option for it, such as the length and the weights.
+func (c Column) withOption(opt Option) (Column, error) {
+	c.Length = opt.LengthOf(c.FullName)
+	c.Weights = opt.WeightsOf(c.FullName)
+	c.SRID, c.BoundingBox = opt.spatialOf(c)
+	if err := c.checkWeights(); err != nil {
+		return Column{}, err
+	}
+	return c, nil
+}
 
-	//TODO: error handling
-	hasParentNodes, _ := cg.HasParentNodes(i)
-	switch hasParentNodes {
-	case false:
+// maxAttemptsForCycle is the limit of the passes to make the values of a cycle of foreign keys consistent
+const maxAttemptsForCycle = 100
+
+// generateValuesForCycle generates the values of the columns which refer to each other, e.g. a.b_id referring to b.id referring to a.b_id.
+// the first column of the cycle is generated without its parent, the others refer to their parents in turn,
+// and then the columns refer to their parents again until every value is one of the parent values.
+// the parents out of the cycle are generated before, see generationOrder.
+func generateValuesForCycle(r *rand.Rand, cg *ColumnGraph, cycle []int, rn RecordNumber, opt Option, dict map[ColumnFullName][]Value) error {
+	inCycle := map[int]bool{}
+	for _, i := range cycle {
+		inCycle[i] = true
+	}
+	firstParentIndex := func(i int) int {
+		parentNodeIndexes, _ := cg.ParentNodeIndexes(i)
+		if len(parentNodeIndexes) == 0 {
+			return -1
+		}
+		return parentNodeIndexes[0]
+	}
+	generate := func(i, p int) error {
 		c := cg.ColumnNodes[i].GetColumn()
-		d := c.GenerateData(n)
+		n := rn.Of(c.FullName.TableName())
+		if p < 0 {
+			d, err := generateRootValues(r, c, n, cg.ColumnNodes[i].unique, opt)
+			dict[c.FullName] = d
+			return err
+		}
+		parent := cg.ColumnNodes[p].GetColumn()
+		d, err := coverParentValues(r, c, referableValues(parent, dict[parent.FullName]), n, cg.ColumnNodes[i].unique, c.nullRate(opt))
 		dict[c.FullName] = d
-		cg.ColumnNodes[i].Done()
-		if hasChildrenNodes, _ := cg.HasChildrenNodes(i); hasChildrenNodes {
-			childrenNodesIndexes, _ := cg.ChildrenNodeIndexes(i)
-			for _, childrenNodeIndex := range childrenNodesIndexes {
-				if allDone, _ := cg.IsParentNodesAreAllDone(childrenNodeIndex); allDone {
-					generateValuesForColumnsByRecursion(cg, childrenNodeIndex, n, dict)
+		return err
+	}
+	generated := map[int]bool{}
+	for len(generated) < len(cycle) {
+		progressed := false
+		for _, i := range cycle {
+			if p := firstParentIndex(i); !generated[i] && (!inCycle[p] || generated[p] && p != i) {
+				if err := generate(i, p); err != nil {
+					return err
 				}
+				generated[i], progressed = true, true
 			}
 		}
-	default:
-		allDone, _ := cg.IsParentNodesAreAllDone(i)
-		switch allDone {
-		case true:
-			//TODO: distinct values and data
-			values := []Value{}
-			for j := 0; j < n; j++ {
-				d := ""
-				parentNodeIndexes, _ := cg.ParentNodeIndexes(i)
-				for _, parentNodeIndex := range parentNodeIndexes {
-					fn := cg.ColumnNodes[parentNodeIndex].GetColumn().FullName
-					d += string(dict[fn][j])
+		if progressed {
+			continue
+		}
+		// the first column not generated yet starts the cycle
+		for _, i := range cycle {
+			if !generated[i] {
+				if err := generate(i, -1); err != nil {
+					return err
 				}
-				values = append(values, Value(d))
+				generated[i] = true
+				break
 			}
-			dict[cg.ColumnNodes[i].GetColumn().FullName] = values
-			cg.ColumnNodes[i].Done()
-			if hasChildrenNodes, _ := cg.HasChildrenNodes(i); hasChildrenNodes {
-				childrenNodesIndexes, _ := cg.ChildrenNodeIndexes(i)
-				for _, childrenNodeIndex := range childrenNodesIndexes {
-					if allDone, _ := cg.IsParentNodesAreAllDone(childrenNodeIndex); allDone {
-						generateValuesForColumnsByRecursion(cg, childrenNodeIndex, n, dict)
-					}
-				}
+		}
+	}
+
+	for attempt := 0; ; attempt++ {
+		consistent := true
+		for _, i := range cycle {
+			p := firstParentIndex(i)
+			if !inCycle[p] || p == i {
+				continue
 			}
-		default:
-			parentNodeIndexes, _ := cg.ParentNodeIndexes(i)
-			for _, parentIndex := range parentNodeIndexes {
-				if !cg.ColumnNodes[parentIndex].IsDone() {
-					generateValuesForColumnsByRecursion(cg, parentIndex, n, dict)
-				}
+			c, parent := cg.ColumnNodes[i].GetColumn(), cg.ColumnNodes[p].GetColumn()
+			if c.AutoIncrement || isSubset(dict[c.FullName], referableValues(parent, dict[parent.FullName])) {
+				continue
 			}
+			consistent = false
+			if err := generate(i, p); err != nil {
+				return err
+			}
+		}
+		if consistent {
+			break
+		}
+		if attempt >= maxAttemptsForCycle {
+			return errors.Errorf("cannot generate consistent values for the cycle of foreign keys on %s", cg.ColumnNodes[cycle[0]].GetColumn().FullName)
+		}
+	}
+
+	for _, i := range cycle {
+		cg.ColumnNodes[i].Done()
+	}
+	return nil
+}
+
+// isSubset tells whether every value except NULL is one of the parent values.
+func isSubset(values, parentValues []Value) bool {
+	set := make(map[Value]bool, len(parentValues))
+	for _, v := range parentValues {
+		set[v] = true
+	}
+	for _, v := range values {
+		if !v.IsNull() && !set[v] {
+			return false
+		}
+	}
+	return true
+}
+
+// coverParentValues chooses n values out of the parent values like referParentValues,
+// but uses every distinct parent value as far as possible, so that the columns on a cycle can have the same values.
+func coverParentValues(r *rand.Rand, c Column, parentValues []Value, n int, unique bool, nullRate float64) ([]Value, error) {
+	if unique {
+		return referParentValues(r, c, parentValues, n, Cardinality{}, true, nullRate)
+	}
+	values := make([]Value, n)
+	rows := []int{}
+	for j := range values {
+		if isNull(r, nullRate) {
+			values[j] = NullValue
+		} else {
+			rows = append(rows, j)
+		}
+	}
+	distinct := distinctValues(c, parentValues)
+	if len(rows) > 0 && len(distinct) == 0 {
+		return nil, errors.Errorf("cannot generate values for %s, because the referred column has no values", c.FullName)
+	}
+	r.Shuffle(len(rows), func(a, b int) { rows[a], rows[b] = rows[b], rows[a] })
+	perm := r.Perm(len(distinct))
+	for k, j := range rows {
+		if k < len(perm) {
+			values[j] = distinct[perm[k]]
+		} else {
+			values[j] = distinct[r.Intn(len(distinct))]
+		}
+	}
+	return values, nil
+}
+
+// referParentValues chooses n values out of the parent values along the cardinality, or NULL at the rate of nullRate.
+// when the column is unique, each parent value is chosen at most once whatever the cardinality is.
+func referParentValues(r *rand.Rand, c Column, parentValues []Value, n int, cardinality Cardinality, unique bool, nullRate float64) ([]Value, error) {
+	values := make([]Value, n)
+	m := 0
+	for j := range values {
+		if isNull(r, nullRate) {
+			values[j] = NullValue
+		} else {
+			m++
+		}
+	}
+
+	if unique {
+		distinct := distinctValues(c, parentValues)
+		if len(distinct) < m {
+			return nil, errors.Errorf("cannot generate %d unique values for %s, because the referred column has only %d distinct values", m, c.FullName, len(distinct))
+		}
+		perm := r.Perm(len(distinct))
+		for j := range values {
+			if !values[j].IsNull() {
+				values[j] = distinct[perm[0]]
+				perm = perm[1:]
+			}
+		}
+		return values, nil
+	}
+
+	if m > 0 && len(parentValues) == 0 {
+		return nil, errors.Errorf("cannot generate values for %s, because the referred column has no values", c.FullName)
+	}
+	for j := range values {
+		if !values[j].IsNull() {
+			values[j] = parentValues[cardinality.ParentIndex(r, j, len(parentValues))]
+		}
+	}
+	return values, nil
+}
+
+// distinctValues returns the values which are distinct in the keys of the column, see uniqueValue.
+func distinctValues(c Column, values []Value) []Value {
+	seen := make(map[Value]bool, len(values))
+	distinct := make([]Value, 0, len(values))
+	for _, v := range values {
+		if u := c.uniqueValue(v); !seen[u] {
+			seen[u] = true
+			distinct = append(distinct, v)
+		}
+	}
+	return distinct
+}
+
+// referableValues returns the values which child rows can refer to, which are not NULL.
+// the values of an auto increment column are decided by the database, see autoIncrementValue.
+func referableValues(c Column, values []Value) []Value {
+	if c.AutoIncrement {
+		ids := make([]Value, 0, len(values))
+		for i := range values {
+			ids = append(ids, c.autoIncrementValue(i))
+		}
+		return ids
+	}
+	referable := make([]Value, 0, len(values))
+	for _, v := range values {
+		if !v.IsNull() {
+			referable = append(referable, v)
 		}
 	}
+	return referable
 }
@@ -1,31 +1,29 @@
 package model
 
 import (
+	"sort"
+
 	"github.com/pkg/errors"
 )
 
-// Graph structure of columns expressed by adjacency matrix and nodes
+// Graph structure of columns expressed by adjacency lists and nodes
 type ColumnGraph struct {
-	AdjacencyMatrix AdjacencyMatrix
-	ColumnNodes     []ColumnNode
+	AdjacencyList AdjacencyList
+	ColumnNodes   []ColumnNode
+	// keys is the composite primary keys and unique keys of the tables, as the indexes of the nodes of their columns.
+	// the keys with a column out of the graph are left out
+	keys [][]int
 }
 
-type AdjacencyMatrix [][]int
-
-func newAdjacencyMatrix(n int) AdjacencyMatrix {
-	am := make(AdjacencyMatrix, 0, n)
-	i := 0
-	for i < n {
-		am = append(am, make([]int, n))
-		i++
-	}
-	return am
-}
+// AdjacencyList is the indexes of the parent nodes of each node, i.e. the columns referred to by its foreign keys, in ascending order
+type AdjacencyList [][]int
 
 type ColumnNode struct {
 	column Column
 	isDone bool
 	index  int
+	// unique is true if the column is a primary key or a unique key by itself
+	unique bool
 }
 
 func (cn *ColumnNode) Done() {
@@ -45,38 +43,75 @@ func GenerateColumnGraph(schema Schema) ColumnGraph {
 	columnToIndex := map[string]int{}
 	i := 0
 	for _, table := range schema.Tables {
+		uniqueColumns := map[ColumnName]bool{}
+		for _, key := range table.Keys() {
+			if len(key) == 1 {
+				uniqueColumns[key[0]] = true
+			}
+		}
 		for _, column := range table.Columns {
 			columnToIndex[string(table.Name)+"."+string(column.Name)] = i
 			columnNodes = append(columnNodes, ColumnNode{
 				column: column,
 				isDone: false,
 				index:  i,
+				unique: uniqueColumns[column.Name],
 			})
 			i += 1
 		}
 	}
 
-	am := newAdjacencyMatrix(len(columnToIndex))
+	var keys [][]int
 	for _, table := range schema.Tables {
-		for _, column := range table.Columns {
-			if column.HasConstraint() {
-				if i, ok := columnToIndex[string(table.Name)+"."+string(column.Name)]; ok {
-					for _, constraint := range column.Constraints {
-						if j, ok := columnToIndex[string(constraint.TableName)+"."+string(constraint.ColumnName)]; ok {
-							am[i][j] = 1
-						}
-					}
+		for _, key := range table.Keys() {
+			indexes := []int{}
+			for _, cn := range key {
+				// the values of a generated column are computed by the database, so the key is left to it
+				if j, ok := columnToIndex[string(table.Name)+"."+string(cn)]; ok && !columnNodes[j].column.Generated {
+					indexes = append(indexes, j)
+				}
+			}
+			if len(key) > 1 && len(indexes) == len(key) {
+				keys = append(keys, indexes)
+			}
+		}
+	}
+
+	al := make(AdjacencyList, len(columnNodes))
+	for i, node := range columnNodes {
+		al[i] = []int{}
+		// a generated column is not inserted, so its foreign keys are not followed on the generation
+		if node.column.Generated {
+			continue
+		}
+		// a column of a composite foreign key depends on all the columns referred to by the key, see parentTuples
+		for _, constraint := range node.column.Constraints {
+			for _, cn := range constraint.referencedColumns() {
+				if j, ok := columnToIndex[string(constraint.TableName)+"."+string(cn)]; ok && !containsIndex(al[i], j) {
+					al[i] = append(al[i], j)
 				}
 			}
 		}
+		// the first parent is the first in the schema, see firstParent
+		sort.Ints(al[i])
 	}
 
 	return ColumnGraph{
-		AdjacencyMatrix: am,
-		ColumnNodes:     columnNodes,
+		AdjacencyList: al,
+		ColumnNodes:   columnNodes,
+		keys:          keys,
 	}
 }
 
+func (cg ColumnGraph) column(fn ColumnFullName) (Column, bool) {
+	for _, cn := range cg.ColumnNodes {
+		if cn.column.FullName == fn {
+			return cn.column, true
+		}
+	}
+	return Column{}, false
+}
+
 func (cg ColumnGraph) isAllDone() bool {
 	for _, cn := range cg.ColumnNodes {
 		if !cn.isDone {
@@ -88,65 +123,144 @@ func (cg ColumnGraph) isAllDone() bool {
 
 //TODO: adopt error handling such as Stacktrace
 func (cg ColumnGraph) HasParentNodes(i int) (bool, error) {
-	if i >= len(cg.AdjacencyMatrix) {
+	if i >= len(cg.AdjacencyList) {
 		return false, errors.New("invalid index")
 	}
-	for _, v := range cg.AdjacencyMatrix[i] {
-		if v == 1 {
-			return true, nil
-		}
-	}
-	return false, nil
+	return len(cg.AdjacencyList[i]) > 0, nil
 }
 
 func (cg ColumnGraph) IsParentNodesAreAllDone(i int) (bool, error) {
-	if i >= len(cg.AdjacencyMatrix) {
+	if i >= len(cg.AdjacencyList) {
 		return false, errors.New("invalid index")
 	}
-	for parentIndex, v := range cg.AdjacencyMatrix[i] {
-		if v == 1 {
-			if !cg.ColumnNodes[parentIndex].IsDone() {
-				return false, nil
-			}
+	for _, parentIndex := range cg.AdjacencyList[i] {
+		if !cg.ColumnNodes[parentIndex].IsDone() {
+			return false, nil
 		}
 	}
 	return true, nil
 }
 
 func (cg ColumnGraph) ParentNodeIndexes(i int) ([]int, error) {
-	if i >= len(cg.AdjacencyMatrix) {
+	if i >= len(cg.AdjacencyList) {
 		return []int{}, errors.New("invalid index")
 	}
-	parentNodeIndexes := []int{}
-	for i, v := range cg.AdjacencyMatrix[i] {
-		if v == 1 {
-			parentNodeIndexes = append(parentNodeIndexes, i)
-		}
-	}
-	return parentNodeIndexes, nil
+	return cg.AdjacencyList[i], nil
 }
 
 func (cg ColumnGraph) HasChildrenNodes(i int) (bool, error) {
-	if i >= len(cg.AdjacencyMatrix) {
-		return false, errors.New("invalid index")
-	}
-	for _, r := range cg.AdjacencyMatrix {
-		if r[i] == 1 {
-			return true, nil
-		}
-	}
-	return false, nil
+	childrenNodeIndexes, err := cg.ChildrenNodeIndexes(i)
+	return len(childrenNodeIndexes) > 0, err
 }
 
+// ChildrenNodeIndexes returns the indexes of the nodes referring to the node, in ascending order.
+// it walks all the edges, so use childrenLists to get the children of every node.
 func (cg ColumnGraph) ChildrenNodeIndexes(i int) ([]int, error) {
-	if i >= len(cg.AdjacencyMatrix) {
+	if i >= len(cg.AdjacencyList) {
 		return []int{}, errors.New("invalid index")
 	}
 	childrenNodeIndexes := []int{}
-	for ri, r := range cg.AdjacencyMatrix {
-		if r[i] == 1 {
-			childrenNodeIndexes = append(childrenNodeIndexes, ri)
+	for ci, parents := range cg.AdjacencyList {
+		if containsIndex(parents, i) {
+			childrenNodeIndexes = append(childrenNodeIndexes, ci)
 		}
 	}
 	return childrenNodeIndexes, nil
 }
+
+// childrenLists returns the indexes of the children of each node in ascending order, by walking the edges once.
+func (cg ColumnGraph) childrenLists() [][]int {
+	children := make([][]int, len(cg.AdjacencyList))
+	for ci, parents := range cg.AdjacencyList {
+		for _, p := range parents {
+			children[p] = append(children[p], ci)
+		}
+	}
+	return children
+}
+
+// Cycles returns the groups of the nodes which depend on each other through foreign keys,
+// i.e. the strongly connected components of more than one node and the nodes referring to themselves.
+// the nodes of each group are in the order of the index.
+func (cg ColumnGraph) Cycles() [][]int {
+	parents := cg.AdjacencyList
+	n := len(parents)
+
+	// Tarjan's algorithm with an explicit stack of frames instead of recursion
+	type frame struct {
+		node int
+		next int
+	}
+	order := make([]int, n)
+	low := make([]int, n)
+	for i := range order {
+		order[i] = -1
+	}
+	onStack := make([]bool, n)
+	stack := []int{}
+	counter := 0
+	visit := func(v int) {
+		order[v], low[v] = counter, counter
+		counter++
+		stack = append(stack, v)
+		onStack[v] = true
+	}
+
+	cycles := [][]int{}
+	for s := 0; s < n; s++ {
+		if order[s] >= 0 {
+			continue
+		}
+		visit(s)
+		frames := []frame{{node: s}}
+		for len(frames) > 0 {
+			f := &frames[len(frames)-1]
+			v := f.node
+			if f.next < len(parents[v]) {
+				w := parents[v][f.next]
+				f.next++
+				if order[w] < 0 {
+					visit(w)
+					frames = append(frames, frame{node: w})
+				} else if onStack[w] && order[w] < low[v] {
+					low[v] = order[w]
+				}
+				continue
+			}
+
+			frames = frames[:len(frames)-1]
+			if len(frames) > 0 {
+				if u := frames[len(frames)-1].node; low[v] < low[u] {
+					low[u] = low[v]
+				}
+			}
+			if low[v] != order[v] {
+				continue
+			}
+			component := []int{}
+			for {
+				w := stack[len(stack)-1]
+				stack = stack[:len(stack)-1]
+				onStack[w] = false
+				component = append(component, w)
+				if w == v {
+					break
+				}
+			}
+			if len(component) > 1 || containsIndex(parents[v], v) {
+				sort.Ints(component)
+				cycles = append(cycles, component)
+			}
+		}
+	}
+	return cycles
+}
+
+func containsIndex(indexes []int, i int) bool {
+	for _, j := range indexes {
+		if j == i {
+			return true
+		}
+	}
+	return false
+}
@@ -7,38 +7,6 @@ import (
 	"github.com/google/go-cmp/cmp"
 )
 
-func TestNewAdjacencyMatrix(t *testing.T) {
-	type args struct {
-		n int
-	}
-	tests := []struct {
-		name string
-		args args
-		want AdjacencyMatrix
-	}{
-		{
-			name: "return n*n zero matrix",
-			args: args{n: 5},
-			want: AdjacencyMatrix{
-				{0, 0, 0, 0, 0},
-				{0, 0, 0, 0, 0},
-				{0, 0, 0, 0, 0},
-				{0, 0, 0, 0, 0},
-				{0, 0, 0, 0, 0},
-			},
-		},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			got := newAdjacencyMatrix(tt.args.n)
-			diff := cmp.Diff(got, tt.want)
-			if diff != "" {
-				t.Error("-:got, +:want", diff)
-			}
-		})
-	}
-}
-
 func TestGenerateColumnGraph(t *testing.T) {
 	type args struct {
 		schema Schema
@@ -111,11 +79,11 @@ func TestGenerateColumnGraph(t *testing.T) {
 				},
 			},
 			want: ColumnGraph{
-				AdjacencyMatrix: AdjacencyMatrix{
-					{0, 0, 1, 0},
-					{0, 0, 0, 0},
-					{0, 0, 0, 0},
-					{0, 1, 0, 0},
+				AdjacencyList: AdjacencyList{
+					{2},
+					{},
+					{},
+					{1},
 				},
 				ColumnNodes: []ColumnNode{
 					ColumnNode{
@@ -185,7 +153,7 @@ func TestGenerateColumnGraph(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			got := GenerateColumnGraph(tt.args.schema)
-			diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(ColumnNode{}))
+			diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(ColumnGraph{}, ColumnNode{}))
 			if diff != "" {
 				t.Errorf("GenerateColumnGraph(); -got, +want\n%v", diff)
 			}
@@ -245,7 +213,7 @@ func TestColumnGraph_isAllDone(t *testing.T) {
 
 func TestColumnGraph_HasParentNodes(t *testing.T) {
 	type fields struct {
-		AdjacencyMatrix AdjacencyMatrix
+		AdjacencyList AdjacencyList
 	}
 	type args struct {
 		i int
@@ -260,11 +228,11 @@ func TestColumnGraph_HasParentNodes(t *testing.T) {
 		{
 			name: "return true if the node with the given index has parent nodes",
 			fields: fields{
-				AdjacencyMatrix: AdjacencyMatrix{
-					{0, 0, 1, 0},
-					{0, 0, 0, 0},
-					{0, 0, 0, 0},
-					{0, 1, 0, 0},
+				AdjacencyList: AdjacencyList{
+					{2},
+					{},
+					{},
+					{1},
 				},
 			},
 			args:    args{i: 3},
@@ -274,11 +242,11 @@ func TestColumnGraph_HasParentNodes(t *testing.T) {
 		{
 			name: "return false if the node with the given index does not have parent nodes",
 			fields: fields{
-				AdjacencyMatrix: AdjacencyMatrix{
-					{0, 0, 1, 0},
-					{0, 0, 0, 0},
-					{0, 0, 0, 0},
-					{0, 1, 0, 0},
+				AdjacencyList: AdjacencyList{
+					{2},
+					{},
+					{},
+					{1},
 				},
 			},
 			args:    args{i: 2},
@@ -289,7 +257,7 @@ func TestColumnGraph_HasParentNodes(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cg := ColumnGraph{
-				AdjacencyMatrix: tt.fields.AdjacencyMatrix,
+				AdjacencyList: tt.fields.AdjacencyList,
 			}
 			got, err := cg.HasParentNodes(tt.args.i)
 			if (err != nil) != tt.wantErr {
@@ -305,8 +273,8 @@ func TestColumnGraph_HasParentNodes(t *testing.T) {
 
 func TestColumnGraph_IsParentNodesAreAllDone(t *testing.T) {
 	type fields struct {
-		AdjacencyMatrix AdjacencyMatrix
-		ColumnNodes     []ColumnNode
+		AdjacencyList AdjacencyList
+		ColumnNodes   []ColumnNode
 	}
 	type args struct {
 		i int
@@ -321,11 +289,11 @@ func TestColumnGraph_IsParentNodesAreAllDone(t *testing.T) {
 		{
 			name: "return true if isDone values of parent nodes of the node with the given index are all true",
 			fields: fields{
-				AdjacencyMatrix: AdjacencyMatrix{
-					{0, 0, 1, 0},
-					{0, 0, 0, 0},
-					{0, 0, 0, 0},
-					{0, 1, 0, 0},
+				AdjacencyList: AdjacencyList{
+					{2},
+					{},
+					{},
+					{1},
 				},
 				ColumnNodes: []ColumnNode{
 					{isDone: true},
@@ -341,11 +309,11 @@ func TestColumnGraph_IsParentNodesAreAllDone(t *testing.T) {
 		{
 			name: "return false if one of isDone values of parent nodes of the node with the given index is false",
 			fields: fields{
-				AdjacencyMatrix: AdjacencyMatrix{
-					{0, 0, 1, 0},
-					{0, 0, 0, 0},
-					{0, 0, 0, 0},
-					{0, 1, 0, 0},
+				AdjacencyList: AdjacencyList{
+					{2},
+					{},
+					{},
+					{1},
 				},
 				ColumnNodes: []ColumnNode{
 					{isDone: true},
@@ -362,8 +330,8 @@ func TestColumnGraph_IsParentNodesAreAllDone(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cg := ColumnGraph{
-				AdjacencyMatrix: tt.fields.AdjacencyMatrix,
-				ColumnNodes:     tt.fields.ColumnNodes,
+				AdjacencyList: tt.fields.AdjacencyList,
+				ColumnNodes:   tt.fields.ColumnNodes,
 			}
 			got, err := cg.IsParentNodesAreAllDone(tt.args.i)
 			if (err != nil) != tt.wantErr {
@@ -379,7 +347,7 @@ func TestColumnGraph_IsParentNodesAreAllDone(t *testing.T) {
 
 func TestColumnGraph_ParentNodeIndexes(t *testing.T) {
 	type fields struct {
-		AdjacencyMatrix AdjacencyMatrix
+		AdjacencyList AdjacencyList
 	}
 	type args struct {
 		i int
@@ -394,11 +362,11 @@ func TestColumnGraph_ParentNodeIndexes(t *testing.T) {
 		{
 			name: "return indexes of parent nodes of the node with the given index",
 			fields: fields{
-				AdjacencyMatrix: AdjacencyMatrix{
-					{0, 0, 1, 1},
-					{0, 0, 0, 0},
-					{0, 0, 0, 0},
-					{0, 1, 1, 0},
+				AdjacencyList: AdjacencyList{
+					{2, 3},
+					{},
+					{},
+					{1, 2},
 				},
 			},
 			args:    args{i: 0},
@@ -408,11 +376,11 @@ func TestColumnGraph_ParentNodeIndexes(t *testing.T) {
 		{
 			name: "return empty slice if the node with the given index has no parent nodes",
 			fields: fields{
-				AdjacencyMatrix: AdjacencyMatrix{
-					{0, 0, 1, 0},
-					{0, 0, 0, 0},
-					{0, 0, 0, 0},
-					{0, 1, 0, 0},
+				AdjacencyList: AdjacencyList{
+					{2},
+					{},
+					{},
+					{1},
 				},
 			},
 			args:    args{i: 2},
@@ -423,7 +391,7 @@ func TestColumnGraph_ParentNodeIndexes(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cg := ColumnGraph{
-				AdjacencyMatrix: tt.fields.AdjacencyMatrix,
+				AdjacencyList: tt.fields.AdjacencyList,
 			}
 			got, err := cg.ParentNodeIndexes(tt.args.i)
 			if (err != nil) != tt.wantErr {
@@ -439,7 +407,7 @@ func TestColumnGraph_ParentNodeIndexes(t *testing.T) {
 
 func TestColumnGraph_HasChildrenNodes(t *testing.T) {
 	type fields struct {
-		AdjacencyMatrix AdjacencyMatrix
+		AdjacencyList AdjacencyList
 	}
 	type args struct {
 		i int
@@ -454,11 +422,11 @@ func TestColumnGraph_HasChildrenNodes(t *testing.T) {
 		{
 			name: "return true if the node with the given index has children nodes",
 			fields: fields{
-				AdjacencyMatrix: AdjacencyMatrix{
-					{0, 0, 1, 0},
-					{0, 0, 0, 0},
-					{0, 0, 0, 0},
-					{0, 1, 0, 0},
+				AdjacencyList: AdjacencyList{
+					{2},
+					{},
+					{},
+					{1},
 				},
 			},
 			args:    args{i: 2},
@@ -468,11 +436,11 @@ func TestColumnGraph_HasChildrenNodes(t *testing.T) {
 		{
 			name: "return false if the node with the given index does not have children nodes",
 			fields: fields{
-				AdjacencyMatrix: AdjacencyMatrix{
-					{0, 0, 1, 0},
-					{0, 0, 0, 0},
-					{0, 0, 0, 0},
-					{0, 1, 0, 0},
+				AdjacencyList: AdjacencyList{
+					{2},
+					{},
+					{},
+					{1},
 				},
 			},
 			args:    args{i: 0},
@@ -483,7 +451,7 @@ func TestColumnGraph_HasChildrenNodes(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cg := ColumnGraph{
-				AdjacencyMatrix: tt.fields.AdjacencyMatrix,
+				AdjacencyList: tt.fields.AdjacencyList,
 			}
 			got, err := cg.HasChildrenNodes(tt.args.i)
 			if (err != nil) != tt.wantErr {
@@ -499,7 +467,7 @@ func TestColumnGraph_HasChildrenNodes(t *testing.T) {
 
 func TestColumnGraph_ChildrenNodeIndexes(t *testing.T) {
 	type fields struct {
-		AdjacencyMatrix AdjacencyMatrix
+		AdjacencyList AdjacencyList
 	}
 	type args struct {
 		i int
@@ -514,11 +482,11 @@ func TestColumnGraph_ChildrenNodeIndexes(t *testing.T) {
 		{
 			name: "return indexes of children nodes of the node with the given index",
 			fields: fields{
-				AdjacencyMatrix: AdjacencyMatrix{
-					{0, 0, 1, 0},
-					{0, 0, 0, 0},
-					{0, 0, 0, 0},
-					{0, 1, 1, 0},
+				AdjacencyList: AdjacencyList{
+					{2},
+					{},
+					{},
+					{1, 2},
 				},
 			},
 			args:    args{i: 2},
@@ -528,11 +496,11 @@ func TestColumnGraph_ChildrenNodeIndexes(t *testing.T) {
 		{
 			name: "return empty slice if the node with the given index has no children nodes",
 			fields: fields{
-				AdjacencyMatrix: AdjacencyMatrix{
-					{0, 0, 1, 0},
-					{0, 0, 0, 0},
-					{0, 0, 0, 0},
-					{0, 1, 0, 0},
+				AdjacencyList: AdjacencyList{
+					{2},
+					{},
+					{},
+					{1},
 				},
 			},
 			args:    args{i: 0},
@@ -543,7 +511,7 @@ func TestColumnGraph_ChildrenNodeIndexes(t *testing.T) {
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
 			cg := ColumnGraph{
-				AdjacencyMatrix: tt.fields.AdjacencyMatrix,
+				AdjacencyList: tt.fields.AdjacencyList,
 			}
 			got, err := cg.ChildrenNodeIndexes(tt.args.i)
 			if (err != nil) != tt.wantErr {
@@ -556,3 +524,52 @@ func TestColumnGraph_ChildrenNodeIndexes(t *testing.T) {
 		})
 	}
 }
+
+func TestColumnGraph_Cycles(t *testing.T) {
+	type fields struct {
+		AdjacencyList AdjacencyList
+	}
+	tests := []struct {
+		name   string
+		fields fields
+		want   [][]int
+	}{
+		{
+			name: "return no cycle for a graph without cycles",
+			fields: fields{
+				AdjacencyList: AdjacencyList{
+					{1},
+					{2},
+					{},
+				},
+			},
+			want: [][]int{},
+		},
+		{
+			name: "return the nodes referring to each other and the node referring to itself",
+			fields: fields{
+				AdjacencyList: AdjacencyList{
+					{2},
+					{},
+					{3},
+					{0, 1},
+					{4},
+				},
+			},
+			want: [][]int{{0, 2, 3}, {4}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cg := ColumnGraph{
+				AdjacencyList: tt.fields.AdjacencyList,
+				ColumnNodes:   make([]ColumnNode, len(tt.fields.AdjacencyList)),
+			}
+			got := cg.Cycles()
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("ColumnGraph.Cycles(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"math/rand"
 	"strconv"
 	"testing"
 
@@ -53,7 +54,8 @@ func TestColumn_GenerateData(t *testing.T) {
 		Constraints   []Constraint
 	}
 	type args struct {
-		n int
+		n        int
+		nullRate float64
 	}
 	tests := []struct {
 		name   string
@@ -62,7 +64,7 @@ func TestColumn_GenerateData(t *testing.T) {
 		want   []Value
 	}{
 		{
-			name: "return slice of 'NULL' when AutoIncrement is true",
+			name: "return slice of NULL when AutoIncrement is true",
 			fields: fields{
 				Name:     "test",
 				FullName: "test",
@@ -73,7 +75,20 @@ func TestColumn_GenerateData(t *testing.T) {
 				Constraints:   []Constraint{},
 			},
 			args: args{n: 3},
-			want: []Value{Value("NULL"), Value("NULL"), Value("NULL")},
+			want: []Value{NullValue, NullValue, NullValue},
+		},
+		{
+			name: "return slice of NULL when the null rate is 1",
+			fields: fields{
+				Name:     "test",
+				FullName: "test",
+				Type: ColumnType{
+					Base:  Varchar,
+					Param: 8,
+				},
+			},
+			args: args{n: 2, nullRate: 1},
+			want: []Value{NullValue, NullValue},
 		},
 	}
 	for _, tt := range tests {
@@ -85,7 +100,7 @@ func TestColumn_GenerateData(t *testing.T) {
 				AutoIncrement: tt.fields.AutoIncrement,
 				Constraints:   tt.fields.Constraints,
 			}
-			got := c.GenerateData(tt.args.n)
+			got := c.GenerateData(rand.New(rand.NewSource(1)), tt.args.n, tt.args.nullRate)
 			diff := cmp.Diff(got, tt.want)
 			if diff != "" {
 				t.Error("Column.GenerateData(); -:got, +:want", diff)
@@ -96,23 +111,25 @@ func TestColumn_GenerateData(t *testing.T) {
 
 func TestGenerateValuesForColumns(t *testing.T) {
 	type args struct {
-		cg ColumnGraph
-		n  int
+		cg  ColumnGraph
+		rn  RecordNumber
+		opt Option
 	}
 	tests := []struct {
 		name     string
 		args     args
 		assertFn func(map[ColumnFullName][]Value)
+		wantErr  bool
 	}{
 		{
 			name: "return map of values for columns considering foreign key constraints",
 			args: args{
 				cg: ColumnGraph{
-					AdjacencyMatrix: AdjacencyMatrix{
-						{0, 0, 0, 1},
-						{0, 0, 0, 0},
-						{0, 0, 0, 0},
-						{0, 1, 1, 0},
+					AdjacencyList: AdjacencyList{
+						{3},
+						{},
+						{},
+						{1, 2},
 					},
 					ColumnNodes: []ColumnNode{
 						ColumnNode{
@@ -161,30 +178,32 @@ func TestGenerateValuesForColumns(t *testing.T) {
 						},
 					},
 				},
-				n: 3,
+				rn:  NewRecordNumber(3),
+				opt: NewOption(),
 			},
 			assertFn: func(m map[ColumnFullName][]Value) {
 				for key, vs := range m {
 					switch key {
 					case "test0":
 						for idx, v := range vs {
-							if v != m["test3"][idx] {
-								t.Errorf("values of test1 is not valid; idx: %v, value: %v", idx, v)
+							if !containsValue(m["test3"], v) {
+								t.Errorf("values of test0 is not valid; idx: %v, value: %v", idx, v)
 							}
 						}
 					case "test1", "test2":
 						for idx, v := range vs {
-							n, err := strconv.Atoi(string(v))
+							n, err := strconv.ParseInt(string(v.Data), 10, 64)
 							if err != nil {
 								t.Errorf("cannot convert value to int; value: %v", v)
 							}
-							if !(intRangeMap[Int][0] <= n || n <= intRangeMap[Int][1]) {
+							if !(intRangeMap[Int][0] <= n && n <= intRangeMap[Int][1]) {
 								t.Errorf("values of %v is out of range; idx: %v, value: %v", key, idx, v)
 							}
 						}
 					case "test3":
+						// test3 refers to test1, the first of its parents
 						for idx, v := range vs {
-							if v != m["test1"][idx]+m["test2"][idx] {
+							if !containsValue(m["test1"], v) {
 								t.Errorf("values of test3 is not valid; idx: %v, value: %v", idx, v)
 							}
 						}
@@ -192,11 +211,243 @@ func TestGenerateValuesForColumns(t *testing.T) {
 				}
 			},
 		},
+		{
+			name: "refer to parent values by the cardinality, with ids for auto increment parent",
+			args: args{
+				cg: ColumnGraph{
+					AdjacencyList: AdjacencyList{
+						{},
+						{0},
+					},
+					ColumnNodes: []ColumnNode{
+						{column: Column{FullName: "parent.id", Type: ColumnType{Base: Int}, AutoIncrement: true}, index: 0},
+						{column: Column{FullName: "child.parent_id", Type: ColumnType{Base: Int}}, index: 1},
+					},
+				},
+				rn: NewRecordNumber(5),
+				opt: Option{
+					ColumnCardinalities: map[ColumnFullName]Cardinality{
+						"child.parent_id": {Kind: Exact, K: 2},
+					},
+				},
+			},
+			assertFn: func(m map[ColumnFullName][]Value) {
+				diff := cmp.Diff(m["child.parent_id"], []Value{NewIntValue(1), NewIntValue(1), NewIntValue(2), NewIntValue(2), NewIntValue(3)})
+				if diff != "" {
+					t.Errorf("values of child.parent_id is not valid; -got, +want\n%v", diff)
+				}
+			},
+		},
+		{
+			name: "refer to parent values when the parent table has fewer records than the child table",
+			args: args{
+				cg: ColumnGraph{
+					AdjacencyList: AdjacencyList{
+						{},
+						{0},
+					},
+					ColumnNodes: []ColumnNode{
+						{column: Column{FullName: "country.code", Type: ColumnType{Base: Varchar, Param: 2}}, index: 0},
+						{column: Column{FullName: "city.country_code", Type: ColumnType{Base: Varchar, Param: 2}}, index: 1},
+					},
+				},
+				rn:  RecordNumber{Default: 100, Tables: map[TableName]int{"country": 3}},
+				opt: NewOption(),
+			},
+			assertFn: func(m map[ColumnFullName][]Value) {
+				if len(m["country.code"]) != 3 || len(m["city.country_code"]) != 100 {
+					t.Errorf("the # of values is not valid; country.code: %v, city.country_code: %v", len(m["country.code"]), len(m["city.country_code"]))
+				}
+				for idx, v := range m["city.country_code"] {
+					if !containsValue(m["country.code"], v) {
+						t.Errorf("values of city.country_code is not valid; idx: %v, value: %v", idx, v)
+					}
+				}
+			},
+		},
+		{
+			name: "generate distinct values for unique columns, including ones referring to parents",
+			args: args{
+				cg: ColumnGraph{
+					AdjacencyList: AdjacencyList{
+						{},
+						{0},
+					},
+					ColumnNodes: []ColumnNode{
+						{column: Column{FullName: "user.code", Type: ColumnType{Base: Varchar, Param: 1}}, index: 0, unique: true},
+						{column: Column{FullName: "profile.user_code", Type: ColumnType{Base: Varchar, Param: 1}}, index: 1, unique: true},
+					},
+				},
+				// varchar(1) has 36 distinct values in keys, whose letters are case-insensitive
+				rn:  RecordNumber{Default: 36, Tables: map[TableName]int{"profile": 30}},
+				opt: NewOption(),
+			},
+			assertFn: func(m map[ColumnFullName][]Value) {
+				for _, fn := range []ColumnFullName{"user.code", "profile.user_code"} {
+					if len(distinctValues(Column{Type: ColumnType{Base: Varchar, Param: 1}}, m[fn])) != len(m[fn]) {
+						t.Errorf("values of %v are not distinct; %v", fn, m[fn])
+					}
+				}
+				for idx, v := range m["profile.user_code"] {
+					if !containsValue(m["user.code"], v) {
+						t.Errorf("values of profile.user_code is not valid; idx: %v, value: %v", idx, v)
+					}
+				}
+			},
+		},
+		{
+			name: "generate NULL at the null rate only for nullable columns, and never refer to NULL of parents",
+			args: args{
+				cg: ColumnGraph{
+					AdjacencyList: AdjacencyList{
+						{},
+						{0},
+						{},
+					},
+					ColumnNodes: []ColumnNode{
+						{column: Column{FullName: "user.code", Type: ColumnType{Base: Varchar, Param: 8}}, index: 0},
+						{column: Column{FullName: "profile.user_code", Type: ColumnType{Base: Varchar, Param: 8}, NotNull: true}, index: 1},
+						{column: Column{FullName: "profile.bio", Type: ColumnType{Base: Varchar, Param: 8}}, index: 2},
+					},
+				},
+				rn: NewRecordNumber(100),
+				opt: Option{
+					NullRate:        0.5,
+					ColumnNullRates: map[ColumnFullName]float64{"profile.bio": 1},
+				},
+			},
+			assertFn: func(m map[ColumnFullName][]Value) {
+				if !containsValue(m["user.code"], NullValue) {
+					t.Errorf("values of user.code have no NULL; %v", m["user.code"])
+				}
+				if containsValue(m["profile.user_code"], NullValue) {
+					t.Errorf("values of NOT NULL column profile.user_code have NULL; %v", m["profile.user_code"])
+				}
+				diff := cmp.Diff(distinctValues(Column{}, m["profile.bio"]), []Value{NullValue})
+				if diff != "" {
+					t.Errorf("values of profile.bio is not valid; -got, +want\n%v", diff)
+				}
+			},
+		},
+		{
+			name: "return error when the type of the unique column cannot have enough distinct values",
+			args: args{
+				cg: ColumnGraph{
+					AdjacencyList: AdjacencyList{{}},
+					ColumnNodes: []ColumnNode{
+						{column: Column{FullName: "user.flag", Type: ColumnType{Base: Tinyint, Param: 1}}, index: 0, unique: true},
+					},
+				},
+				rn:  NewRecordNumber(3),
+				opt: NewOption(),
+			},
+			assertFn: func(m map[ColumnFullName][]Value) {},
+			wantErr:  true,
+		},
+		{
+			name: "return error when the parent cannot give enough distinct values to the unique column",
+			args: args{
+				cg: ColumnGraph{
+					AdjacencyList: AdjacencyList{
+						{},
+						{0},
+					},
+					ColumnNodes: []ColumnNode{
+						{column: Column{FullName: "user.id", Type: ColumnType{Base: Int}, AutoIncrement: true}, index: 0},
+						{column: Column{FullName: "profile.user_id", Type: ColumnType{Base: Int}}, index: 1, unique: true},
+					},
+				},
+				rn:  RecordNumber{Default: 5, Tables: map[TableName]int{"user": 4}},
+				opt: NewOption(),
+			},
+			assertFn: func(m map[ColumnFullName][]Value) {},
+			wantErr:  true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := GenerateValuesForColumns(tt.args.cg, tt.args.n)
+			got, err := GenerateValuesForColumns(rand.New(rand.NewSource(1)), tt.args.cg, tt.args.rn, tt.args.opt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GenerateValuesForColumns() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
 			tt.assertFn(got)
 		})
 	}
 }
+
+func values(ds ...ColumnData) []Value {
+	vs := make([]Value, 0, len(ds))
+	for _, d := range ds {
+		vs = append(vs, NewStringValue(string(d)))
+	}
+	return vs
+}
+
+func containsValue(vs []Value, v Value) bool {
+	for _, w := range vs {
+		if w == v {
+			return true
+		}
+	}
+	return false
+}
+
+func Test_generationOrder(t *testing.T) {
+	// a chain longer than the recursion could handle, where node i refers to node i+1
+	chain := make(AdjacencyList, 100000)
+	for i := range chain {
+		chain[i] = []int{}
+		if i+1 < len(chain) {
+			chain[i] = []int{i + 1}
+		}
+	}
+	type args struct {
+		cg ColumnGraph
+	}
+	tests := []struct {
+		name     string
+		args     args
+		assertFn func(*testing.T, [][]int)
+	}{
+		{
+			name: "put the parents before the children, and the nodes of a cycle together",
+			args: args{
+				cg: ColumnGraph{
+					AdjacencyList: AdjacencyList{
+						{3},
+						{},
+						{0},
+						{2, 1},
+						{4},
+					},
+				},
+			},
+			assertFn: func(t *testing.T, got [][]int) {
+				diff := cmp.Diff(got, [][]int{{1}, {4}, {0, 2, 3}})
+				if diff != "" {
+					t.Errorf("generationOrder(); -got, +want\n%v", diff)
+				}
+			},
+		},
+		{
+			name: "order a long chain of foreign keys",
+			args: args{cg: ColumnGraph{AdjacencyList: chain}},
+			assertFn: func(t *testing.T, got [][]int) {
+				if len(got) != len(chain) {
+					t.Fatalf("the # of groups = %v", len(got))
+				}
+				for k, group := range got {
+					if diff := cmp.Diff(group, []int{len(chain) - 1 - k}); diff != "" {
+						t.Fatalf("the %v-th group; -got, +want\n%v", k, diff)
+					}
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.assertFn(t, generationOrder(tt.args.cg))
+		})
+	}
+}
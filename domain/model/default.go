@@ -0,0 +1,34 @@
+package model
+
+import "strings"
+
+// defaultableColumns returns the columns whose values can be left to their DEFAULT clauses.
+// the columns referred to by foreign keys, with foreign keys or in keys keep generated values,
+// because the default value may break the constraints.
+// DEFAULT NULL is not counted, which mysqldump writes for every nullable column.
+func defaultableColumns(schema Schema) map[ColumnFullName]bool {
+	referred := referredColumns(schema)
+	defaultable := map[ColumnFullName]bool{}
+	for _, table := range schema.Tables {
+		inKey := map[ColumnName]bool{}
+		for _, key := range table.Keys() {
+			for _, cn := range key {
+				inKey[cn] = true
+			}
+		}
+		for _, c := range table.Columns {
+			if c.HasDefault && !strings.EqualFold(strings.TrimSpace(c.Default), "NULL") && !c.AutoIncrement && !c.Generated && !c.HasConstraint() && !referred[c.FullName] && !inKey[c.Name] {
+				defaultable[c.FullName] = true
+			}
+		}
+	}
+	return defaultable
+}
+
+// omittedColumns returns the columns left out of INSERT statements, other than auto increment columns.
+func omittedColumns(schema Schema, opt Option) map[ColumnFullName]bool {
+	if !opt.OmitDefaults {
+		return map[ColumnFullName]bool{}
+	}
+	return defaultableColumns(schema)
+}
@@ -0,0 +1,73 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_defaultableColumns(t *testing.T) {
+	type args struct {
+		schema Schema
+	}
+	tests := []struct {
+		name string
+		args args
+		want map[ColumnFullName]bool
+	}{
+		{
+			name: "return columns with DEFAULT clauses except ones in keys or foreign keys",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{
+							Name: "user",
+							Columns: []Column{
+								{Name: "id", FullName: "user.id", AutoIncrement: true, Default: "NULL", HasDefault: true},
+								{Name: "status", FullName: "user.status", Default: "'active'", HasDefault: true},
+								{Name: "code", FullName: "user.code", Default: "'x'", HasDefault: true},
+								{Name: "role", FullName: "user.role", Default: "'a'", HasDefault: true},
+								{Name: "name", FullName: "user.name"},
+							},
+							UniqueKeys: []Key{{"code"}},
+						},
+						{
+							Name: "profile",
+							Columns: []Column{
+								{Name: "user_role", FullName: "profile.user_role", Default: "'a'", HasDefault: true, Constraints: []Constraint{{TableName: "user", ColumnName: "role"}}},
+							},
+						},
+					},
+				},
+			},
+			want: map[ColumnFullName]bool{"user.status": true},
+		},
+		{
+			name: "leave out columns with DEFAULT NULL, which is the same as no DEFAULT clause",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{
+							Name: "user",
+							Columns: []Column{
+								{Name: "nickname", FullName: "user.nickname", Default: "NULL", HasDefault: true},
+								{Name: "memo", FullName: "user.memo", Default: "null", HasDefault: true},
+								{Name: "status", FullName: "user.status", Default: "'NULL'", HasDefault: true},
+							},
+						},
+					},
+				},
+			},
+			want: map[ColumnFullName]bool{"user.status": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := defaultableColumns(tt.args.schema)
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("defaultableColumns(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,55 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Dialect is the SQL dialect which the generated queries are written in
+type Dialect string
+
+const (
+	// MySQL treats backslashes in string literals as escape characters
+	MySQL Dialect = "mysql"
+	// ANSI follows standard SQL, where only quotes are escaped by doubling them, e.g. PostgreSQL, SQLite and MySQL with ANSI sql_mode
+	ANSI Dialect = "ansi"
+)
+
+var DefaultDialect = MySQL
+
+// mysqlEscaper escapes the characters which break string literals of MySQL or are unreadable in dump files
+var mysqlEscaper = strings.NewReplacer(
+	`\`, `\\`,
+	`'`, `\'`,
+	"\x00", `\0`,
+	"\n", `\n`,
+	"\r", `\r`,
+	"\x1a", `\Z`,
+)
+
+var ansiEscaper = strings.NewReplacer(`'`, `''`)
+
+func ParseDialect(str string) (Dialect, error) {
+	switch d := Dialect(strings.ToLower(strings.TrimSpace(str))); d {
+	case MySQL, ANSI:
+		return d, nil
+	default:
+		return "", errors.Errorf("unknown dialect %q, it must be mysql or ansi", str)
+	}
+}
+
+// QuoteIdentifier quotes the name of a table or a column.
+func (d Dialect) QuoteIdentifier(name string) string {
+	if d == ANSI {
+		return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+	}
+	return "`" + strings.ReplaceAll(name, "`", "``") + "`"
+}
+
+func (d Dialect) quoteString(s string) string {
+	if d == ANSI {
+		return "'" + ansiEscaper.Replace(s) + "'"
+	}
+	return "'" + mysqlEscaper.Replace(s) + "'"
+}
@@ -0,0 +1,82 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseDialect(t *testing.T) {
+	type args struct {
+		str string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Dialect
+		wantErr bool
+	}{
+		{
+			name: "parse mysql",
+			args: args{str: "MySQL"},
+			want: MySQL,
+		},
+		{
+			name: "parse ansi",
+			args: args{str: " ansi "},
+			want: ANSI,
+		},
+		{
+			name:    "return error for unknown dialect",
+			args:    args{str: "oracle"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDialect(tt.args.str)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDialect() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("ParseDialect(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestDialect_QuoteIdentifier(t *testing.T) {
+	type args struct {
+		name string
+	}
+	tests := []struct {
+		name    string
+		dialect Dialect
+		args    args
+		want    string
+	}{
+		{
+			name:    "quote by backticks for mysql",
+			dialect: MySQL,
+			args:    args{name: "a`b"},
+			want:    "`a``b`",
+		},
+		{
+			name:    "quote by double quotes for ansi",
+			dialect: ANSI,
+			args:    args{name: `a"b`},
+			want:    `"a""b"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.dialect.QuoteIdentifier(tt.args.name)
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("Dialect.QuoteIdentifier(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
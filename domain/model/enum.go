@@ -0,0 +1,136 @@
+package model
+
+import (
+	"math"
+	"math/rand"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// defaultSetMemberRate is the rate of each member of SET in the values, for the members without weights
+const defaultSetMemberRate = 0.5
+
+// weightOf returns the weight of the member, comparing the names case-insensitively like MySQL.
+func weightOf(weights map[string]float64, member string) (float64, bool) {
+	return lookupByName(weights, member)
+}
+
+// enumRates returns the rates of the members of ENUM, where the members without weights share the rest of the weights equally.
+// when every member has its weight, the rates are the weights in proportion.
+func enumRates(members []string, weights map[string]float64) []float64 {
+	rates := make([]float64, len(members))
+	rest, unweighted := 1.0, 0
+	for k, m := range members {
+		if w, ok := weightOf(weights, m); ok {
+			rates[k] = w
+			rest -= w
+		} else {
+			rates[k] = -1
+			unweighted++
+		}
+	}
+	for k := range rates {
+		if rates[k] < 0 {
+			rates[k] = math.Max(rest, 0) / float64(unweighted)
+		}
+	}
+	return rates
+}
+
+// generateRandomEnum returns one of the members at the rates of enumRates.
+func generateRandomEnum(r *rand.Rand, members []string, weights map[string]float64) Value {
+	if len(members) == 0 {
+		return NewStringValue("")
+	}
+	rates := enumRates(members, weights)
+	total := 0.0
+	for _, rate := range rates {
+		total += rate
+	}
+	x := r.Float64() * total
+	for k, rate := range rates {
+		if x < rate {
+			return NewStringValue(members[k])
+		}
+		x -= rate
+	}
+	return NewStringValue(members[len(members)-1])
+}
+
+// generateRandomSet returns a subset of the members joined by commas in the order of the definition, e.g. a,c.
+// each member is in the subset at the rate of its weight, or defaultSetMemberRate without it.
+func generateRandomSet(r *rand.Rand, members []string, weights map[string]float64) Value {
+	subset := []string{}
+	for _, m := range members {
+		rate, ok := weightOf(weights, m)
+		if !ok {
+			rate = defaultSetMemberRate
+		}
+		if r.Float64() < rate {
+			subset = append(subset, m)
+		}
+	}
+	return NewStringValue(strings.Join(subset, ","))
+}
+
+// memberDomainSize returns the # of distinct values of ENUM or SET which the weights allow.
+// the members of the rate 0 never appear, and the members of SET of the rate 1 always appear.
+func (c Column) memberDomainSize() uint64 {
+	if c.Type.Base == Enum {
+		size := uint64(0)
+		for _, rate := range enumRates(c.Type.Members, c.Weights) {
+			if rate > 0 {
+				size++
+			}
+		}
+		return size
+	}
+	free := 0
+	for _, m := range c.Type.Members {
+		rate, ok := weightOf(c.Weights, m)
+		if !ok || (rate > 0 && rate < 1) {
+			free++
+		}
+	}
+	return powSaturated(2, free)
+}
+
+// checkWeights returns error if the weights of the column are not of its members,
+// or the weights of ENUM leave no member to choose or exceed 1 in total.
+func (c Column) checkWeights() error {
+	if len(c.Weights) == 0 {
+		return nil
+	}
+	if c.Type.Base != Enum && c.Type.Base != Set {
+		return errors.Errorf("the weights of %s are available only for ENUM and SET", c.FullName)
+	}
+	total := 0.0
+	for name, w := range c.Weights {
+		found := false
+		for _, m := range c.Type.Members {
+			found = found || strings.EqualFold(m, name)
+		}
+		if !found {
+			return errors.Errorf("%q is not a member of %s", name, c.FullName)
+		}
+		if w < 0 || w > 1 {
+			return errors.Errorf("the weight of %q of %s is %v, it must be from 0 to 1", name, c.FullName, w)
+		}
+		total += w
+	}
+	if c.Type.Base == Set {
+		return nil
+	}
+	if total > 1+1e-9 {
+		return errors.Errorf("the weights of %s are %v in total, it must not exceed 1", c.FullName, total)
+	}
+	positive := false
+	for _, rate := range enumRates(c.Type.Members, c.Weights) {
+		positive = positive || rate > 0
+	}
+	if !positive {
+		return errors.Errorf("the weights of %s leave no member of it to generate", c.FullName)
+	}
+	return nil
+}
@@ -0,0 +1,109 @@
+package model
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_generateRandomEnum(t *testing.T) {
+	members := []string{"draft", "published", "archived"}
+	type args struct {
+		weights map[string]float64
+	}
+	tests := []struct {
+		name string
+		args args
+		// want is the expected rates of the members
+		want map[string]float64
+	}{
+		{
+			name: "choose every member equally without weights",
+			args: args{},
+			want: map[string]float64{"draft": 1.0 / 3, "published": 1.0 / 3, "archived": 1.0 / 3},
+		},
+		{
+			name: "share the rest of the weights among the members without weights",
+			args: args{weights: map[string]float64{"Published": 0.9}},
+			want: map[string]float64{"draft": 0.05, "published": 0.9, "archived": 0.05},
+		},
+		{
+			name: "never choose the members of the weight 0",
+			args: args{weights: map[string]float64{"draft": 0, "archived": 0}},
+			want: map[string]float64{"published": 1},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			n := 10000
+			counts := map[string]int{}
+			for i := 0; i < n; i++ {
+				counts[string(generateRandomEnum(r, members, tt.args.weights).Data)]++
+			}
+			for m, c := range counts {
+				if _, ok := tt.want[m]; !ok {
+					t.Fatalf("unexpected member %q", m)
+				}
+				if rate := float64(c) / float64(n); rate < tt.want[m]-0.02 || rate > tt.want[m]+0.02 {
+					t.Errorf("the rate of %q = %v, want %v", m, rate, tt.want[m])
+				}
+			}
+		})
+	}
+}
+
+func Test_generateRandomSet(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	members := []string{"a", "b", "c"}
+	got := map[string]bool{}
+	for i := 0; i < 1000; i++ {
+		got[string(generateRandomSet(r, members, map[string]float64{"a": 1, "b": 0}).Data)] = true
+	}
+	diff := cmp.Diff(got, map[string]bool{"a": true, "a,c": true})
+	if diff != "" {
+		t.Errorf("generateRandomSet(); -got, +want\n%v", diff)
+	}
+}
+
+func TestColumn_checkWeights(t *testing.T) {
+	enum := ColumnType{Base: Enum, Members: []string{"draft", "published"}}
+	tests := []struct {
+		name    string
+		column  Column
+		wantErr bool
+	}{
+		{
+			name:   "accept the weights of the members",
+			column: Column{FullName: "article.status", Type: enum, Weights: map[string]float64{"published": 0.9}},
+		},
+		{
+			name:    "return error for a weight of an unknown member",
+			column:  Column{FullName: "article.status", Type: enum, Weights: map[string]float64{"deleted": 0.1}},
+			wantErr: true,
+		},
+		{
+			name:    "return error for the weights of ENUM over 1 in total",
+			column:  Column{FullName: "article.status", Type: enum, Weights: map[string]float64{"draft": 0.5, "published": 0.6}},
+			wantErr: true,
+		},
+		{
+			name:    "return error for the weights of ENUM leaving no member",
+			column:  Column{FullName: "article.status", Type: enum, Weights: map[string]float64{"draft": 0, "published": 0}},
+			wantErr: true,
+		},
+		{
+			name:    "return error for the weights of a column other than ENUM and SET",
+			column:  Column{FullName: "article.title", Type: ColumnType{Base: Varchar, Param: 8}, Weights: map[string]float64{"a": 0.5}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.column.checkWeights(); (err != nil) != tt.wantErr {
+				t.Errorf("Column.checkWeights() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,334 @@
+package model
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// parentTuples is the rows of the parent table which a composite foreign key can refer to,
+// so that all the columns of the key take their values from the same row.
+type parentTuples struct {
+	// rows is the values of the referenced columns in the order of the key, for the rows without NULL.
+	// the rows with the same values are kept once.
+	rows []Record
+	// index is the rows by the values of some of the referenced columns, made for each set of the positions on demand
+	index map[string]map[string][]int
+}
+
+// newParentTuples makes the tuples out of the values of the referenced columns, which are generated in advance.
+func newParentTuples(referenced []Column, stored map[ColumnFullName][]Value) *parentTuples {
+	pt := &parentTuples{index: map[string]map[string][]int{}}
+	n := -1
+	for _, c := range referenced {
+		if m := len(stored[c.FullName]); n < 0 || m < n {
+			n = m
+		}
+	}
+	all := make([]int, len(referenced))
+	for k := range all {
+		all[k] = k
+	}
+	seen := map[string]bool{}
+	for j := 0; j < n; j++ {
+		row := make(Record, 0, len(referenced))
+		for _, c := range referenced {
+			v := stored[c.FullName][j]
+			// the same as the values referred to, see referableValues
+			if c.AutoIncrement {
+				v = c.autoIncrementValue(j)
+			}
+			row = append(row, v)
+		}
+		k, hasNull := tupleKey(row, all)
+		if hasNull || seen[k] {
+			continue
+		}
+		seen[k] = true
+		pt.rows = append(pt.rows, row)
+	}
+	return pt
+}
+
+// find returns the rows which have the values at the positions.
+func (pt *parentTuples) find(positions []int, values []Value) []int {
+	ps := make([]string, 0, len(positions))
+	for _, k := range positions {
+		ps = append(ps, strconv.Itoa(k))
+	}
+	mask := strings.Join(ps, ",")
+	index, ok := pt.index[mask]
+	if !ok {
+		index = map[string][]int{}
+		for j, row := range pt.rows {
+			k, _ := tupleKey(row, positions)
+			index[k] = append(index[k], j)
+		}
+		pt.index[mask] = index
+	}
+	indexes := make([]int, len(values))
+	for k := range indexes {
+		indexes[k] = k
+	}
+	k, _ := tupleKey(values, indexes)
+	return index[k]
+}
+
+// chooseRow returns the row which the i-th child row refers to, out of the rows having the values at the positions.
+// ok is false if no row has the values.
+func (pt *parentTuples) chooseRow(r *rand.Rand, i int, cardinality Cardinality, positions []int, values []Value) (int, bool) {
+	if len(positions) == 0 {
+		if len(pt.rows) == 0 {
+			return 0, false
+		}
+		return cardinality.ParentIndex(r, i, len(pt.rows)), true
+	}
+	rows := pt.find(positions, values)
+	if len(rows) == 0 {
+		return 0, false
+	}
+	return rows[r.Intn(len(rows))], true
+}
+
+// referencedColumnsOf returns the columns referred to by the composite foreign key, in the order of the key.
+func referencedColumnsOf(constraint Constraint, schema Schema) ([]Column, error) {
+	columns := make([]Column, 0, len(constraint.ReferencedColumns))
+	for _, cn := range constraint.ReferencedColumns {
+		c, ok := schema.column(constraint.TableName, cn)
+		if !ok {
+			return nil, errors.Errorf("column %s referred to by the foreign key (%s) is not defined", NewColumnFullName(constraint.TableName, cn), joinKey(constraint.Columns))
+		}
+		columns = append(columns, c)
+	}
+	return columns, nil
+}
+
+// referParentTuples chooses n values of a column of a composite foreign key out of the parent rows,
+// or NULL at the rate of nullRate.
+// siblings is the values of the other columns of the key generated already, and the i-th value comes from a row having their i-th values.
+// when the column is unique, each value is chosen at most once.
+func referParentTuples(r *rand.Rand, c Column, constraint Constraint, tuples *parentTuples, siblings map[int][]Value, n int, cardinality Cardinality, unique bool, nullRate float64) ([]Value, error) {
+	position := 0
+	for k, cn := range constraint.Columns {
+		if cn == c.Name {
+			position = k
+		}
+	}
+	values := make([]Value, n)
+	var perm []int
+	used := map[Value]bool{}
+	if unique {
+		perm = r.Perm(len(tuples.rows))
+	}
+	for i := range values {
+		if isNull(r, nullRate) {
+			values[i] = NullValue
+			continue
+		}
+		positions, known := []int{}, []Value{}
+		for k, vs := range siblings {
+			if !vs[i].IsNull() {
+				positions = append(positions, k)
+				known = append(known, vs[i])
+			}
+		}
+		sortPositions(positions, known)
+
+		var row int
+		ok := false
+		switch {
+		case unique && len(positions) == 0:
+			for !ok && len(perm) > 0 {
+				row, perm = perm[0], perm[1:]
+				ok = !used[tuples.rows[row][position]]
+			}
+		case unique:
+			rows := tuples.find(positions, known)
+			if len(rows) == 0 {
+				break
+			}
+			offset := r.Intn(len(rows))
+			for k := range rows {
+				row = rows[(offset+k)%len(rows)]
+				if ok = !used[tuples.rows[row][position]]; ok {
+					break
+				}
+			}
+		default:
+			row, ok = tuples.chooseRow(r, i, cardinality, positions, known)
+		}
+		if !ok {
+			return nil, errors.Errorf("cannot generate values for %s, because no row of %s is left to refer to by the foreign key (%s)", c.FullName, constraint.TableName, joinKey(constraint.Columns))
+		}
+		values[i] = tuples.rows[row][position]
+		if unique {
+			used[values[i]] = true
+		}
+	}
+	return values, nil
+}
+
+// sortPositions sorts the positions in ascending order together with their values, so that the index of parentTuples is shared.
+func sortPositions(positions []int, values []Value) {
+	for a := 1; a < len(positions); a++ {
+		for b := a; b > 0 && positions[b-1] > positions[b]; b-- {
+			positions[b-1], positions[b] = positions[b], positions[b-1]
+			values[b-1], values[b] = values[b], values[b-1]
+		}
+	}
+}
+
+// generateTupleValues generates the values of a column of a composite foreign key in ColumnGraph,
+// consistently with the other columns of the key generated already.
+func generateTupleValues(r *rand.Rand, cg *ColumnGraph, c Column, constraint Constraint, n int, unique bool, opt Option, dict map[ColumnFullName][]Value) ([]Value, error) {
+	referenced := make([]Column, 0, len(constraint.ReferencedColumns))
+	for _, cn := range constraint.ReferencedColumns {
+		parent, _ := cg.column(NewColumnFullName(constraint.TableName, cn))
+		referenced = append(referenced, parent)
+	}
+	siblings := map[int][]Value{}
+	for k, cn := range constraint.Columns {
+		sibling, ok := cg.column(NewColumnFullName(c.FullName.TableName(), cn))
+		if !ok || sibling.Name == c.Name {
+			continue
+		}
+		// a column following another composite foreign key does not always match the rows
+		if sc, ok := sibling.compositeConstraint(); !ok || sc.key() != constraint.key() {
+			continue
+		}
+		if values, ok := dict[sibling.FullName]; ok && !sibling.AutoIncrement {
+			siblings[k] = values
+		}
+	}
+	return referParentTuples(r, c, constraint, newParentTuples(referenced, dict), siblings, n, opt.CardinalityOf(c.FullName), unique, c.nullRate(opt))
+}
+
+// foreignKeyStream sets the values of the columns of a composite foreign key row by row, out of the same parent row.
+// the columns generated in advance are kept, and the others take the values of a parent row matching them.
+type foreignKeyStream struct {
+	table      TableName
+	constraint Constraint
+	tuples     *parentTuples
+	// indexes is the positions of the columns of the key in the record, in the order of the key.
+	// it is -1 for the columns not inserted and the columns following another composite foreign key.
+	indexes     []int
+	cardinality Cardinality
+	// perm is the order in which the parent rows are used, when the columns of the key make a unique key of the table
+	perm []int
+}
+
+func newForeignKeyStream(r *rand.Rand, table Table, constraint Constraint, schema Schema, n int, opt Option, stored map[ColumnFullName][]Value, columns []Column, index map[ColumnName]int) (*foreignKeyStream, error) {
+	referenced, err := referencedColumnsOf(constraint, schema)
+	if err != nil {
+		return nil, err
+	}
+	fs := &foreignKeyStream{
+		table:       table.Name,
+		constraint:  constraint,
+		tuples:      newParentTuples(referenced, stored),
+		cardinality: opt.CardinalityOf(NewColumnFullName(table.Name, constraint.Columns[0])),
+	}
+	notNull := true
+	for _, cn := range constraint.Columns {
+		j, ok := index[cn]
+		if ok {
+			if cc, _ := columns[j].compositeConstraint(); cc.key() != constraint.key() {
+				ok = false
+			}
+		}
+		if !ok {
+			j = -1
+		} else {
+			notNull = notNull && columns[j].nullRate(opt) == 0
+		}
+		fs.indexes = append(fs.indexes, j)
+	}
+	for _, key := range table.Keys() {
+		if sameColumns(key, constraint.Columns) {
+			if notNull && len(fs.tuples.rows) < n {
+				return nil, errors.Errorf("cannot generate %d unique values for the foreign key (%s) of %s, because %s has only %d rows to refer to", n, joinKey(constraint.Columns), table.Name, constraint.TableName, len(fs.tuples.rows))
+			}
+			fs.perm = r.Perm(len(fs.tuples.rows))
+			break
+		}
+	}
+	return fs, nil
+}
+
+// generate sets the values of the columns of the key in the i-th row, each of which is NULL at its null rate.
+func (fs *foreignKeyStream) generate(r *rand.Rand, i int, record Record, streams []*columnStream) error {
+	targets := []int{}
+	for k, j := range fs.indexes {
+		if j < 0 || streams[j].stored != nil {
+			continue
+		}
+		if isNull(r, streams[j].nullRate) {
+			record[j] = NullValue
+			continue
+		}
+		targets = append(targets, k)
+	}
+	return fs.fill(r, i, record, targets, fs.cardinality, fs.perm != nil)
+}
+
+// regenerate replaces the values of the columns of the key at the indexes of the record by the values of another parent row,
+// which matches the other columns of the key.
+func (fs *foreignKeyStream) regenerate(r *rand.Rand, record Record, indexes []int) error {
+	targets := []int{}
+	for k, j := range fs.indexes {
+		if j >= 0 && containsIndex(indexes, j) {
+			targets = append(targets, k)
+		}
+	}
+	return fs.fill(r, 0, record, targets, DefaultCardinality, false)
+}
+
+// fill sets the values at the positions of targets out of a parent row, which has the values of the other columns of the key.
+func (fs *foreignKeyStream) fill(r *rand.Rand, i int, record Record, targets []int, cardinality Cardinality, usePerm bool) error {
+	if len(targets) == 0 {
+		return nil
+	}
+	positions, known := []int{}, []Value{}
+	for k, j := range fs.indexes {
+		if j >= 0 && !containsIndex(targets, k) && !record[j].IsNull() {
+			positions = append(positions, k)
+			known = append(known, record[j])
+		}
+	}
+
+	var row int
+	if usePerm && len(positions) == 0 {
+		if len(fs.perm) == 0 {
+			return errors.Errorf("cannot generate unique values for the foreign key (%s) of %s, because %s has only %d rows to refer to", joinKey(fs.constraint.Columns), fs.table, fs.constraint.TableName, len(fs.tuples.rows))
+		}
+		row, fs.perm = fs.perm[0], fs.perm[1:]
+	} else {
+		var ok bool
+		if row, ok = fs.tuples.chooseRow(r, i, cardinality, positions, known); !ok {
+			return errors.Errorf("cannot generate values for the foreign key (%s) of %s, because no row of %s matches", joinKey(fs.constraint.Columns), fs.table, fs.constraint.TableName)
+		}
+	}
+	for _, k := range targets {
+		record[fs.indexes[k]] = fs.tuples.rows[row][k]
+	}
+	return nil
+}
+
+// sameColumns tells whether the key consists of the same columns as the given ones in any order.
+func sameColumns(key Key, columns []ColumnName) bool {
+	if len(key) != len(columns) {
+		return false
+	}
+	for _, cn := range key {
+		found := false
+		for _, other := range columns {
+			found = found || other == cn
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
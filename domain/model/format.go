@@ -0,0 +1,30 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Format is the output format of the generated data
+type Format string
+
+const (
+	// SQL is INSERT statements
+	SQL Format = "sql"
+	// CSV is a header line and records for each table, the tables are separated by an empty line
+	CSV Format = "csv"
+	// JSON is an object from table names to the arrays of records
+	JSON Format = "json"
+)
+
+var DefaultFormat = SQL
+
+func ParseFormat(str string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(str))); f {
+	case SQL, CSV, JSON:
+		return f, nil
+	default:
+		return "", errors.Errorf("unknown format %q, it must be sql, csv or json", str)
+	}
+}
@@ -0,0 +1,43 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseFormat(t *testing.T) {
+	type args struct {
+		str string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    Format
+		wantErr bool
+	}{
+		{
+			name: "parse csv",
+			args: args{str: "CSV"},
+			want: CSV,
+		},
+		{
+			name:    "return error for unknown format",
+			args:    args{str: "xml"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseFormat(tt.args.str)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseFormat() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("ParseFormat(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,56 @@
+package model
+
+import "math/rand"
+
+// DefaultHierarchyDepth is the # of the levels of the trees made by self-referencing foreign keys
+const DefaultHierarchyDepth = 3
+
+// isHierarchy tells whether the column refers to another column of the same table, e.g. employee.manager_id referring to employee.id.
+// the rows of such a table make trees, whose roots have no parent, see hierarchyValue.
+// a unique column does not make trees, because a row can have only one child then,
+// nor does a column of a composite foreign key, which refers to the parent rows together with the other columns.
+func isHierarchy(c, parent Column, unique bool) bool {
+	_, composite := c.compositeConstraint()
+	return parent.FullName.TableName() == c.FullName.TableName() && parent.Name != c.Name && !unique && !composite
+}
+
+// hierarchyValue returns the value of the i-th row out of n rows, which refers to a row in the previous level of the trees.
+// the rows in the first level are the roots, which have NULL, or refer to themselves for a NOT NULL column.
+// parentValues is all the values of the parent column, including NULL.
+func hierarchyValue(r *rand.Rand, c, parent Column, parentValues []Value, i, n, depth int) Value {
+	j, ok := hierarchyParentIndex(r, i, n, depth)
+	if !ok {
+		if !c.NotNull {
+			return NullValue
+		}
+		j = i
+	}
+	// the same as the values referred to, see referableValues
+	if parent.AutoIncrement {
+		return parent.autoIncrementValue(j)
+	}
+	return parentValues[j]
+}
+
+// hierarchyParentIndex returns the index of the parent row of the i-th row out of n rows, which make trees of the given depth.
+// the rows are divided into the levels of the trees in order, so that every parent row comes before its children.
+// ok is false for the rows in the first level.
+func hierarchyParentIndex(r *rand.Rand, i, n, depth int) (int, bool) {
+	if depth > n {
+		depth = n
+	}
+	if depth < 1 {
+		return 0, false
+	}
+	level := i * depth / n
+	if level == 0 {
+		return 0, false
+	}
+	start, end := levelStart(level-1, n, depth), levelStart(level, n, depth)
+	return start + r.Intn(end-start), true
+}
+
+// levelStart returns the index of the first row in the level.
+func levelStart(level, n, depth int) int {
+	return (level*n + depth - 1) / depth
+}
@@ -0,0 +1,46 @@
+package model
+
+import (
+	"math/rand"
+	"testing"
+)
+
+func Test_hierarchyParentIndex(t *testing.T) {
+	type args struct {
+		n     int
+		depth int
+	}
+	tests := []struct {
+		name string
+		args args
+		// want is the range of the parent index for each row, or nil for the roots
+		want [][2]int
+	}{
+		{
+			name: "divide the rows into the levels in order",
+			args: args{n: 7, depth: 3},
+			want: [][2]int{{}, {}, {}, {0, 3}, {0, 3}, {3, 5}, {3, 5}},
+		},
+		{
+			name: "make every row a root for the depth of 1",
+			args: args{n: 3, depth: 1},
+			want: [][2]int{{}, {}, {}},
+		},
+		{
+			name: "make a chain for the depth more than the # of rows",
+			args: args{n: 3, depth: 10},
+			want: [][2]int{{}, {0, 1}, {1, 2}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			for i := 0; i < tt.args.n; i++ {
+				got, ok := hierarchyParentIndex(r, i, tt.args.n, tt.args.depth)
+				if want := tt.want[i]; ok != (want != [2]int{}) || ok && (got < want[0] || got >= want[1]) {
+					t.Errorf("hierarchyParentIndex(%v) = %v, %v, want in %v", i, got, ok, want)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// maxAttemptsForUniqueKey is the limit of regenerating a row whose key is duplicated
+const maxAttemptsForUniqueKey = 1000
+
+// firstParent returns the column which the values of the given column come from.
+// it is the first in the schema out of the referred columns, same as the generation by ColumnGraph.
+func firstParent(c Column, schema Schema) (Column, bool) {
+	for _, table := range schema.Tables {
+		for _, column := range table.Columns {
+			for _, constraint := range c.Constraints {
+				if constraint.TableName == table.Name && constraint.ColumnName == column.Name {
+					return column, true
+				}
+			}
+		}
+	}
+	return Column{}, false
+}
+
+func referredColumns(schema Schema) map[ColumnFullName]bool {
+	referred := map[ColumnFullName]bool{}
+	for _, table := range schema.Tables {
+		for _, column := range table.Columns {
+			for _, constraint := range column.Constraints {
+				for _, cn := range constraint.referencedColumns() {
+					referred[NewColumnFullName(constraint.TableName, cn)] = true
+				}
+			}
+		}
+	}
+	return referred
+}
+
+// tupleKey encodes the values at the indexes of the record into a string without ambiguity.
+// hasNull is true if one of the values is NULL.
+func tupleKey(record Record, indexes []int) (key string, hasNull bool) {
+	var sb strings.Builder
+	for _, j := range indexes {
+		v := record[j]
+		if v.IsNull() {
+			return "", true
+		}
+		sb.WriteString(strconv.Itoa(len(v.Data)))
+		sb.WriteByte(':')
+		sb.WriteString(string(v.Data))
+	}
+	return sb.String(), false
+}
+
+func joinKey(key Key) string {
+	names := make([]string, 0, len(key))
+	for _, cn := range key {
+		names = append(names, string(cn))
+	}
+	return strings.Join(names, ", ")
+}
+
+// generateKeyValues generates the values of the columns of a composite key together, so that the tuples of the rows are distinct.
+// it is only for the key of the columns without parents, none of which is unique by itself nor generated yet,
+// and the other keys are left to the generation of each column.
+func generateKeyValues(r *rand.Rand, cg *ColumnGraph, key []int, rn RecordNumber, opt Option, dict map[ColumnFullName][]Value) error {
+	columns := make([]Column, 0, len(key))
+	for _, i := range key {
+		node := cg.ColumnNodes[i]
+		if len(cg.AdjacencyList[i]) > 0 || node.unique || node.IsDone() || node.column.AutoIncrement {
+			return nil
+		}
+		c, err := node.GetColumn().withOption(opt)
+		if err != nil {
+			return err
+		}
+		columns = append(columns, c)
+	}
+	values, err := generateUniqueTuples(r, columns, rn.Of(columns[0].FullName.TableName()), opt)
+	if err != nil {
+		return err
+	}
+	for k, i := range key {
+		dict[columns[k].FullName] = values[k]
+		cg.ColumnNodes[i].Done()
+	}
+	return nil
+}
+
+// generateUniqueTuples returns n values of each column, whose tuples in the same rows are distinct except the tuples with NULL.
+// it returns error if the types of the columns cannot have enough distinct tuples, same as GenerateUniqueData.
+func generateUniqueTuples(r *rand.Rand, columns []Column, n int, opt Option) ([][]Value, error) {
+	values := make([][]Value, len(columns))
+	for k := range values {
+		values[k] = make([]Value, n)
+	}
+	// the rows with NULL are decided first, which need no uniqueness
+	hasNull := make([]bool, n)
+	m := 0
+	for i := 0; i < n; i++ {
+		for k, c := range columns {
+			if isNull(r, c.nullRate(opt)) {
+				values[k][i] = NullValue
+				hasNull[i] = true
+			}
+		}
+		if !hasNull[i] {
+			m++
+		}
+	}
+	size := uint64(1)
+	key := make(Key, 0, len(columns))
+	indexes := make([]int, 0, len(columns))
+	for k, c := range columns {
+		size = mulSaturated(size, c.domainSize())
+		key = append(key, c.Name)
+		indexes = append(indexes, k)
+	}
+	if size < uint64(m) {
+		return nil, errors.Errorf("cannot generate %d unique values for the key (%s) of %s, because it can have only %d distinct values", m, joinKey(key), columns[0].FullName.TableName(), size)
+	}
+
+	seen := make(map[string]bool, m)
+	record := make(Record, len(columns))
+	for i := 0; i < n; i++ {
+		for {
+			for k, c := range columns {
+				if values[k][i].IsNull() {
+					record[k] = NullValue
+				} else {
+					record[k] = c.GenerateRandomData(r)
+				}
+			}
+			if hasNull[i] {
+				break
+			}
+			if t, _ := uniqueKey(record, indexes, columns); !seen[t] {
+				seen[t] = true
+				break
+			}
+		}
+		for k := range columns {
+			values[k][i] = record[k]
+		}
+	}
+	return values, nil
+}
@@ -0,0 +1,117 @@
+package model
+
+import (
+	"math"
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// LengthRange is the range of the lengths of the values of string and binary columns, in characters or bytes.
+// the zero value means the default length of the type, see Column.lengthRange.
+type LengthRange struct {
+	Min int
+	Max int
+}
+
+// DefaultLargeObjectLength is the length of the values of the TEXT and BLOB families without LengthRange,
+// because their max sizes are too large for dummy data
+const DefaultLargeObjectLength = 100
+
+// the max # of characters or bytes of the string and binary types in MySQL
+var maxLengthMap = map[ColumnTypeBase]int64{
+	Char:       255,
+	Binary:     255,
+	Varchar:    65535,
+	Varbinary:  65535,
+	Tinytext:   255,
+	Text:       65535,
+	Mediumtext: 16777215,
+	Longtext:   math.MaxUint32,
+	Tinyblob:   255,
+	Blob:       65535,
+	Mediumblob: 16777215,
+	Longblob:   math.MaxUint32,
+}
+
+// MaxLengthOf returns the max # of characters or bytes of the type, or 0 for the types other than strings and binary data.
+func MaxLengthOf(t ColumnTypeBase) int64 {
+	return maxLengthMap[t]
+}
+
+// IsLargeObject tells whether the type is one of the TEXT and BLOB families.
+func IsLargeObject(t ColumnTypeBase) bool {
+	switch t {
+	case Tinytext, Text, Mediumtext, Longtext, Tinyblob, Blob, Mediumblob, Longblob:
+		return true
+	default:
+		return false
+	}
+}
+
+// ParseLengthRange converts strings such as "1-20", "0-8" and "8" (the same as "8-8") into LengthRange.
+// the max must be positive, so that it is told from the zero value.
+func ParseLengthRange(str string) (LengthRange, error) {
+	min, max, hasMax := strings.Cut(strings.TrimSpace(str), "-")
+	if !hasMax {
+		max = min
+	}
+	lo, err := strconv.Atoi(strings.TrimSpace(min))
+	if err != nil || lo < 0 {
+		return LengthRange{}, errors.Errorf("length %q needs non-negative numbers, e.g. 1-20 or 8", str)
+	}
+	hi, err := strconv.Atoi(strings.TrimSpace(max))
+	if err != nil || hi < lo || hi == 0 {
+		return LengthRange{}, errors.Errorf("length %q needs the positive max not less than the min, e.g. 1-20 or 8", str)
+	}
+	return LengthRange{Min: lo, Max: hi}, nil
+}
+
+// lengthRange returns the min and the max length of the values of the string or binary column.
+// CHAR and BINARY always have the fixed length of the type, and the others are within the max size of the type.
+func (c Column) lengthRange() (int, int) {
+	size := int(c.Type.Param)
+	switch {
+	case c.Type.Base == Char || c.Type.Base == Binary:
+		return size, size
+	case c.Length != LengthRange{}:
+		return minInt(c.Length.Min, size), minInt(c.Length.Max, size)
+	case IsLargeObject(c.Type.Base):
+		return minInt(DefaultLargeObjectLength, size), minInt(DefaultLargeObjectLength, size)
+	default:
+		return size, size
+	}
+}
+
+// randomLength returns a length within lengthRange of the column.
+func (c Column) randomLength(r *rand.Rand) int {
+	lo, hi := c.lengthRange()
+	if lo == hi {
+		return lo
+	}
+	return lo + r.Intn(hi-lo+1)
+}
+
+// lengthDomainSize returns the # of distinct values of the lengths within lengthRange, made of base kinds of characters or bytes.
+// it saturates at math.MaxUint64.
+func (c Column) lengthDomainSize(base uint64) uint64 {
+	lo, hi := c.lengthRange()
+	size := uint64(0)
+	for l := lo; l <= hi && size < math.MaxUint64; l++ {
+		n := powSaturated(base, l)
+		if size > math.MaxUint64-n {
+			return math.MaxUint64
+		}
+		size += n
+	}
+	return size
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,96 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseLengthRange(t *testing.T) {
+	type args struct {
+		str string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    LengthRange
+		wantErr bool
+	}{
+		{
+			name: "parse the min and the max",
+			args: args{str: " 1-20 "},
+			want: LengthRange{Min: 1, Max: 20},
+		},
+		{
+			name: "parse a single length as the min and the max",
+			args: args{str: "8"},
+			want: LengthRange{Min: 8, Max: 8},
+		},
+		{
+			name:    "return error for the max less than the min",
+			args:    args{str: "20-1"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for the max of 0",
+			args:    args{str: "0"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for non-numeric length",
+			args:    args{str: "short"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLengthRange(tt.args.str)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseLengthRange() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("ParseLengthRange(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestColumn_lengthRange(t *testing.T) {
+	tests := []struct {
+		name   string
+		column Column
+		want   [2]int
+	}{
+		{
+			name:   "use the length of varchar by default",
+			column: Column{Type: ColumnType{Base: Varchar, Param: 20}},
+			want:   [2]int{20, 20},
+		},
+		{
+			name:   "use the default length for text",
+			column: Column{Type: ColumnType{Base: Text, Param: 65535}},
+			want:   [2]int{DefaultLargeObjectLength, DefaultLargeObjectLength},
+		},
+		{
+			name:   "cap the range by the max size of the type",
+			column: Column{Type: ColumnType{Base: Tinyblob, Param: 255}, Length: LengthRange{Min: 10, Max: 1000}},
+			want:   [2]int{10, 255},
+		},
+		{
+			name:   "keep the fixed length of char",
+			column: Column{Type: ColumnType{Base: Char, Param: 4}, Length: LengthRange{Min: 1, Max: 2}},
+			want:   [2]int{4, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lo, hi := tt.column.lengthRange()
+			diff := cmp.Diff([2]int{lo, hi}, tt.want)
+			if diff != "" {
+				t.Errorf("Column.lengthRange(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,160 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Option is the set of user settings for the dummy data generation
+type Option struct {
+	// Cardinality is used for the foreign keys which have no setting in ColumnCardinalities
+	Cardinality         Cardinality
+	ColumnCardinalities map[ColumnFullName]Cardinality
+	// NullRate is the rate of NULL in nullable columns which have no setting in ColumnNullRates, from 0 to 1
+	NullRate        float64
+	ColumnNullRates map[ColumnFullName]float64
+	// Length is the range of the lengths of strings and binary data in the columns which have no setting in ColumnLengths.
+	// the zero value means the default length of the type
+	Length        LengthRange
+	ColumnLengths map[ColumnFullName]LengthRange
+	// SRID is the spatial reference system of the spatial columns without SRID attributes, e.g. 4326. 0 writes the values without SRID
+	SRID int
+	// BoundingBox is the area of the values of the spatial columns which have no setting in ColumnBoundingBoxes.
+	// the zero value means DefaultBoundingBox
+	BoundingBox         BoundingBox
+	ColumnBoundingBoxes map[ColumnFullName]BoundingBox
+	// ColumnWeights is the rates of the members of ENUM and SET columns, e.g. 0.9 for published, see generateRandomEnum and generateRandomSet
+	ColumnWeights map[ColumnFullName]map[string]float64
+	// OmitDefaults leaves the columns with DEFAULT clauses out of INSERT statements
+	OmitDefaults bool
+	// DefaultRate is the rate of DEFAULT in the columns with DEFAULT clauses, from 0 to 1
+	DefaultRate float64
+	// SpecialFloatRate is the rate of the special values in FLOAT and DOUBLE columns, from 0 to 1,
+	// which are 0, -0, and the min and the max magnitude of the type, see generateSpecialFloat
+	SpecialFloatRate float64
+	// Seed is the seed of the random values. the same seed, schema and option give the same data
+	Seed int64
+	// Dialect is the SQL dialect of the queries. DefaultDialect is used when it is empty
+	Dialect Dialect
+	// Format is the output format. SQL is used when it is empty
+	Format Format
+	// BatchSize is the max # of rows in an INSERT statement. no limit when it is 0
+	BatchSize int
+	// MaxStatementBytes is the max size of an INSERT statement in bytes. no limit when it is 0
+	MaxStatementBytes int
+	// TableOrder is the order of the tables in the output. DefaultTableOrder is used when it is empty
+	TableOrder TableOrder
+	// BreakCycles defers some values of the cycles of foreign keys to UPDATE statements in DependencyOrder
+	BreakCycles bool
+	// HierarchyDepth is the # of the levels of the trees made by self-referencing foreign keys. DefaultHierarchyDepth is used when it is 0
+	HierarchyDepth int
+}
+
+func NewOption() Option {
+	return Option{
+		Cardinality:         DefaultCardinality,
+		ColumnCardinalities: map[ColumnFullName]Cardinality{},
+		ColumnNullRates:     map[ColumnFullName]float64{},
+		ColumnLengths:       map[ColumnFullName]LengthRange{},
+		ColumnWeights:       map[ColumnFullName]map[string]float64{},
+		ColumnBoundingBoxes: map[ColumnFullName]BoundingBox{},
+		Dialect:             DefaultDialect,
+		Format:              DefaultFormat,
+	}
+}
+
+// CardinalityOf returns the cardinality for the foreign key on the given column.
+func (o Option) CardinalityOf(fn ColumnFullName) Cardinality {
+	if c, ok := lookupByName(o.ColumnCardinalities, fn); ok {
+		return c
+	}
+	if o.Cardinality.Kind == "" {
+		return DefaultCardinality
+	}
+	return o.Cardinality
+}
+
+// LengthOf returns the range of the lengths of the values of the column.
+func (o Option) LengthOf(fn ColumnFullName) LengthRange {
+	if l, ok := lookupByName(o.ColumnLengths, fn); ok {
+		return l
+	}
+	return o.Length
+}
+
+// WeightsOf returns the rates of the members of the column, or nil if it has no setting.
+func (o Option) WeightsOf(fn ColumnFullName) map[string]float64 {
+	w, _ := lookupByName(o.ColumnWeights, fn)
+	return w
+}
+
+// BoundingBoxOf returns the area of the values of the spatial column.
+func (o Option) BoundingBoxOf(fn ColumnFullName) BoundingBox {
+	if bb, ok := lookupByName(o.ColumnBoundingBoxes, fn); ok {
+		return bb
+	}
+	return o.BoundingBox
+}
+
+// spatialOf returns the SRID and the bounding box of the column, where the SRID attribute of the column takes priority over SRID.
+func (o Option) spatialOf(c Column) (int, BoundingBox) {
+	srid := c.SRID
+	if srid == 0 {
+		srid = o.SRID
+	}
+	return srid, o.BoundingBoxOf(c.FullName)
+}
+
+// Validate returns error for the settings which the format cannot express,
+// e.g. DefaultRate for CSV, whose NULL would be loaded instead of the DEFAULT clause.
+func (o Option) Validate() error {
+	if o.Format != "" && o.Format != SQL && o.DefaultRate > 0 {
+		return errors.Errorf("defaultRate is available only for sql format, because %s cannot express DEFAULT", o.Format)
+	}
+	return nil
+}
+
+func (o Option) dialect() Dialect {
+	if o.Dialect == "" {
+		return DefaultDialect
+	}
+	return o.Dialect
+}
+
+func (o Option) tableOrder() TableOrder {
+	if o.TableOrder == "" {
+		return DefaultTableOrder
+	}
+	return o.TableOrder
+}
+
+func (o Option) hierarchyDepth() int {
+	if o.HierarchyDepth == 0 {
+		return DefaultHierarchyDepth
+	}
+	return o.HierarchyDepth
+}
+
+// NullRateOf returns the rate of NULL for the column if it is nullable.
+func (o Option) NullRateOf(fn ColumnFullName) float64 {
+	if r, ok := lookupByName(o.ColumnNullRates, fn); ok {
+		return r
+	}
+	return o.NullRate
+}
+
+// lookupByName finds the setting for the table or the column.
+// names are compared case-insensitively, because config files may not keep the case of table and column names.
+func lookupByName[K ~string, T any](m map[K]T, name K) (T, bool) {
+	if v, ok := m[name]; ok {
+		return v, true
+	}
+	for k, v := range m {
+		if strings.EqualFold(string(k), string(name)) {
+			return v, true
+		}
+	}
+	var zero T
+	return zero, false
+}
@@ -0,0 +1,154 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestOption_CardinalityOf(t *testing.T) {
+	type args struct {
+		fn ColumnFullName
+	}
+	tests := []struct {
+		name string
+		opt  Option
+		args args
+		want Cardinality
+	}{
+		{
+			name: "return the setting for the column",
+			opt: Option{
+				Cardinality:         Cardinality{Kind: Skewed},
+				ColumnCardinalities: map[ColumnFullName]Cardinality{"product.owner": {Kind: Exact, K: 2}},
+			},
+			args: args{fn: "product.owner"},
+			want: Cardinality{Kind: Exact, K: 2},
+		},
+		{
+			name: "compare column names case-insensitively",
+			opt: Option{
+				ColumnCardinalities: map[ColumnFullName]Cardinality{"product.owner": {Kind: Exact, K: 2}},
+			},
+			args: args{fn: "Product.Owner"},
+			want: Cardinality{Kind: Exact, K: 2},
+		},
+		{
+			name: "return the global setting for the column without its own setting",
+			opt: Option{
+				Cardinality:         Cardinality{Kind: Skewed},
+				ColumnCardinalities: map[ColumnFullName]Cardinality{"product.owner": {Kind: Exact, K: 2}},
+			},
+			args: args{fn: "product.name"},
+			want: Cardinality{Kind: Skewed},
+		},
+		{
+			name: "return uniform when nothing is set",
+			opt:  Option{},
+			args: args{fn: "product.name"},
+			want: Cardinality{Kind: Uniform},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.opt.CardinalityOf(tt.args.fn)
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("Option.CardinalityOf(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestOption_LengthOf(t *testing.T) {
+	opt := Option{
+		Length:        LengthRange{Min: 1, Max: 20},
+		ColumnLengths: map[ColumnFullName]LengthRange{"product.code": {Min: 8, Max: 8}},
+	}
+	tests := []struct {
+		name string
+		fn   ColumnFullName
+		want LengthRange
+	}{
+		{
+			name: "return the setting for the column case-insensitively",
+			fn:   "Product.Code",
+			want: LengthRange{Min: 8, Max: 8},
+		},
+		{
+			name: "return the global setting for the column without its own setting",
+			fn:   "product.name",
+			want: LengthRange{Min: 1, Max: 20},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := opt.LengthOf(tt.fn)
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("Option.LengthOf(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestOption_NullRateOf(t *testing.T) {
+	type args struct {
+		fn ColumnFullName
+	}
+	tests := []struct {
+		name string
+		opt  Option
+		args args
+		want float64
+	}{
+		{
+			name: "return the setting for the column",
+			opt: Option{
+				NullRate:        0.1,
+				ColumnNullRates: map[ColumnFullName]float64{"product.description": 0.5},
+			},
+			args: args{fn: "Product.Description"},
+			want: 0.5,
+		},
+		{
+			name: "return the global setting for the column without its own setting",
+			opt: Option{
+				NullRate:        0.1,
+				ColumnNullRates: map[ColumnFullName]float64{"product.description": 0.5},
+			},
+			args: args{fn: "product.name"},
+			want: 0.1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.opt.NullRateOf(tt.args.fn)
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("Option.NullRateOf(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestOption_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		opt     Option
+		wantErr bool
+	}{
+		{name: "allow DEFAULT in sql", opt: Option{Format: SQL, DefaultRate: 0.5}},
+		{name: "allow DEFAULT in the default format", opt: Option{DefaultRate: 0.5}},
+		{name: "allow csv without DEFAULT", opt: Option{Format: CSV}},
+		{name: "reject DEFAULT in csv", opt: Option{Format: CSV, DefaultRate: 0.5}, wantErr: true},
+		{name: "reject DEFAULT in json", opt: Option{Format: JSON, DefaultRate: 0.5}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.opt.Validate(); (err != nil) != tt.wantErr {
+				t.Errorf("Option.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,175 @@
+package model
+
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// TableOrder is the order in which the records of the tables are inserted
+type TableOrder string
+
+const (
+	// FileOrder inserts the tables in the order of the schema, disabling foreign key checks of MySQL
+	FileOrder TableOrder = "file"
+	// DependencyOrder inserts the referred tables before the referring tables, so that the records can be loaded with foreign key checks
+	DependencyOrder TableOrder = "dependency"
+)
+
+var DefaultTableOrder = FileOrder
+
+func ParseTableOrder(str string) (TableOrder, error) {
+	switch o := TableOrder(strings.ToLower(strings.TrimSpace(str))); o {
+	case FileOrder, DependencyOrder:
+		return o, nil
+	default:
+		return "", errors.Errorf("unknown table order %q, it must be file or dependency", str)
+	}
+}
+
+// tableDependency is a foreign key from a column of a table to the parent table
+type tableDependency struct {
+	column Column
+	parent TableName
+}
+
+// tableDependencies returns the foreign keys of each table along the edges of the column graph.
+// the self references making trees are left out, because every parent row is inserted before its children, see hierarchyValue.
+func tableDependencies(cg ColumnGraph) map[TableName][]tableDependency {
+	deps := map[TableName][]tableDependency{}
+	for i, node := range cg.ColumnNodes {
+		parentIndexes, _ := cg.ParentNodeIndexes(i)
+		for k, j := range parentIndexes {
+			if k == 0 && isHierarchy(node.column, cg.ColumnNodes[j].column, node.unique) {
+				continue
+			}
+			tn := node.column.FullName.TableName()
+			deps[tn] = append(deps[tn], tableDependency{
+				column: node.column,
+				parent: cg.ColumnNodes[j].column.FullName.TableName(),
+			})
+		}
+	}
+	return deps
+}
+
+// orderTables returns the tables in the order of insertion.
+// in DependencyOrder, a cycle of foreign keys is an error unless opt.BreakCycles is true,
+// then the values of some columns in the cycle are deferred, which are inserted as NULL and set by UPDATE statements afterwards.
+func orderTables(schema Schema, opt Option) ([]Table, map[ColumnFullName]bool, error) {
+	deferred := map[ColumnFullName]bool{}
+	if opt.tableOrder() != DependencyOrder {
+		return schema.Tables, deferred, nil
+	}
+
+	deps := tableDependencies(GenerateColumnGraph(schema))
+	referred := referredColumns(schema)
+	ordered := make([]Table, 0, len(schema.Tables))
+	done := map[TableName]bool{}
+	for len(ordered) < len(schema.Tables) {
+		// the tables are taken in the order of the schema as long as possible, to keep the output stable
+		if t, ok := nextTable(schema, deps, deferred, done); ok {
+			ordered = append(ordered, t)
+			done[t.Name] = true
+			continue
+		}
+
+		cyclic := cyclicTables(schema, deps, done)
+		if !opt.BreakCycles {
+			return nil, nil, errors.Errorf("cannot order the tables, because foreign keys make a cycle among %s", joinTableNames(cyclic))
+		}
+		c, ok := deferrableColumn(schema, deps, deferred, done, referred)
+		if !ok {
+			return nil, nil, errors.Errorf("cannot break the cycle of foreign keys among %s, because no column in it is nullable and in a table with a primary key", joinTableNames(cyclic))
+		}
+		deferred[c.FullName] = true
+	}
+	return ordered, deferred, nil
+}
+
+// nextTable returns the first table not done yet whose parents are all done.
+func nextTable(schema Schema, deps map[TableName][]tableDependency, deferred map[ColumnFullName]bool, done map[TableName]bool) (Table, bool) {
+	for _, t := range schema.Tables {
+		if done[t.Name] {
+			continue
+		}
+		ready := true
+		for _, dep := range deps[t.Name] {
+			if !done[dep.parent] && !deferred[dep.column.FullName] {
+				ready = false
+				break
+			}
+		}
+		if ready {
+			return t, true
+		}
+	}
+	return Table{}, false
+}
+
+// deferrableColumn chooses the first column on a cycle of foreign keys among the tables not done yet,
+// which can be NULL on insertion and whose row can be specified by the primary key on update.
+// the columns referred to by other foreign keys are not deferred, because the child rows need their values on insertion.
+func deferrableColumn(schema Schema, deps map[TableName][]tableDependency, deferred map[ColumnFullName]bool, done map[TableName]bool, referred map[ColumnFullName]bool) (Column, bool) {
+	for _, t := range schema.Tables {
+		if done[t.Name] || len(t.PrimaryKey) == 0 {
+			continue
+		}
+		for _, dep := range deps[t.Name] {
+			c := dep.column
+			if done[dep.parent] || deferred[c.FullName] || c.NotNull || c.AutoIncrement || referred[c.FullName] {
+				continue
+			}
+			if reaches(deps, deferred, done, dep.parent, t.Name) {
+				return c, true
+			}
+		}
+	}
+	return Column{}, false
+}
+
+// cyclicTables returns the tables not done yet which are on cycles of foreign keys.
+func cyclicTables(schema Schema, deps map[TableName][]tableDependency, done map[TableName]bool) []TableName {
+	tns := []TableName{}
+	for _, t := range schema.Tables {
+		if done[t.Name] {
+			continue
+		}
+		for _, dep := range deps[t.Name] {
+			if !done[dep.parent] && reaches(deps, nil, done, dep.parent, t.Name) {
+				tns = append(tns, t.Name)
+				break
+			}
+		}
+	}
+	return tns
+}
+
+// reaches tells whether the table from depends on the table to through foreign keys, except the deferred ones and the tables done.
+func reaches(deps map[TableName][]tableDependency, deferred map[ColumnFullName]bool, done map[TableName]bool, from, to TableName) bool {
+	visited := map[TableName]bool{from: true}
+	queue := []TableName{from}
+	for len(queue) > 0 {
+		tn := queue[0]
+		queue = queue[1:]
+		if tn == to {
+			return true
+		}
+		for _, dep := range deps[tn] {
+			if done[dep.parent] || deferred[dep.column.FullName] || visited[dep.parent] {
+				continue
+			}
+			visited[dep.parent] = true
+			queue = append(queue, dep.parent)
+		}
+	}
+	return false
+}
+
+func joinTableNames(tns []TableName) string {
+	names := make([]string, 0, len(tns))
+	for _, tn := range tns {
+		names = append(names, string(tn))
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,171 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseTableOrder(t *testing.T) {
+	type args struct {
+		str string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    TableOrder
+		wantErr bool
+	}{
+		{
+			name: "parse file",
+			args: args{str: "file"},
+			want: FileOrder,
+		},
+		{
+			name: "parse dependency",
+			args: args{str: " Dependency "},
+			want: DependencyOrder,
+		},
+		{
+			name:    "return error for unknown order",
+			args:    args{str: "random"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTableOrder(tt.args.str)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseTableOrder() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("ParseTableOrder(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
+
+func Test_orderTables(t *testing.T) {
+	fk := func(tn TableName, cn ColumnName) []Constraint {
+		return []Constraint{{TableName: tn, ColumnName: cn}}
+	}
+	// orders refers to user, which refers to country
+	chain := Schema{
+		Tables: []Table{
+			{Name: "orders", Columns: []Column{{Name: "user_id", FullName: "orders.user_id", Constraints: fk("user", "id")}}},
+			{Name: "user", Columns: []Column{{Name: "id", FullName: "user.id"}, {Name: "country_id", FullName: "user.country_id", Constraints: fk("country", "id")}}},
+			{Name: "country", Columns: []Column{{Name: "id", FullName: "country.id"}}},
+		},
+	}
+	// a and b refer to each other, and c refers to a
+	cycle := func(aNotNull bool) Schema {
+		return Schema{
+			Tables: []Table{
+				{Name: "c", Columns: []Column{{Name: "a_id", FullName: "c.a_id", Constraints: fk("a", "id")}}},
+				{Name: "a", Columns: []Column{
+					{Name: "id", FullName: "a.id", NotNull: true},
+					{Name: "b_id", FullName: "a.b_id", NotNull: aNotNull, Constraints: fk("b", "id")},
+				}, PrimaryKey: Key{"id"}},
+				{Name: "b", Columns: []Column{
+					{Name: "id", FullName: "b.id", NotNull: true},
+					{Name: "a_id", FullName: "b.a_id", NotNull: true, Constraints: fk("a", "id")},
+				}, PrimaryKey: Key{"id"}},
+			},
+		}
+	}
+	type args struct {
+		schema Schema
+		opt    Option
+	}
+	tests := []struct {
+		name         string
+		args         args
+		want         []TableName
+		wantDeferred map[ColumnFullName]bool
+		wantErr      bool
+	}{
+		{
+			name:         "keep the order of the schema in file order",
+			args:         args{schema: chain, opt: Option{}},
+			want:         []TableName{"orders", "user", "country"},
+			wantDeferred: map[ColumnFullName]bool{},
+		},
+		{
+			name:         "put the referred tables first in dependency order",
+			args:         args{schema: chain, opt: Option{TableOrder: DependencyOrder}},
+			want:         []TableName{"country", "user", "orders"},
+			wantDeferred: map[ColumnFullName]bool{},
+		},
+		{
+			name:    "return error for a cycle without breaking cycles",
+			args:    args{schema: cycle(false), opt: Option{TableOrder: DependencyOrder}},
+			wantErr: true,
+		},
+		{
+			name:         "defer a nullable column to break a cycle",
+			args:         args{schema: cycle(false), opt: Option{TableOrder: DependencyOrder, BreakCycles: true}},
+			want:         []TableName{"a", "c", "b"},
+			wantDeferred: map[ColumnFullName]bool{"a.b_id": true},
+		},
+		{
+			name:    "return error for a cycle of NOT NULL columns",
+			args:    args{schema: cycle(true), opt: Option{TableOrder: DependencyOrder, BreakCycles: true}},
+			wantErr: true,
+		},
+		{
+			name: "ignore a self reference making trees",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "employee", Columns: []Column{
+							{Name: "id", FullName: "employee.id", NotNull: true},
+							{Name: "manager_id", FullName: "employee.manager_id", Constraints: fk("employee", "id")},
+						}, PrimaryKey: Key{"id"}},
+					},
+				},
+				opt: Option{TableOrder: DependencyOrder},
+			},
+			want:         []TableName{"employee"},
+			wantDeferred: map[ColumnFullName]bool{},
+		},
+		{
+			name: "defer a unique self reference",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "employee", Columns: []Column{
+							{Name: "id", FullName: "employee.id", NotNull: true},
+							{Name: "successor_id", FullName: "employee.successor_id", Constraints: fk("employee", "id")},
+						}, PrimaryKey: Key{"id"}, UniqueKeys: []Key{{"successor_id"}}},
+					},
+				},
+				opt: Option{TableOrder: DependencyOrder, BreakCycles: true},
+			},
+			want:         []TableName{"employee"},
+			wantDeferred: map[ColumnFullName]bool{"employee.successor_id": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, deferred, err := orderTables(tt.args.schema, tt.args.opt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("orderTables() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			names := []TableName{}
+			for _, table := range got {
+				names = append(names, table.Name)
+			}
+			diff := cmp.Diff(names, tt.want)
+			diff += cmp.Diff(deferred, tt.wantDeferred)
+			if diff != "" {
+				t.Errorf("orderTables(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
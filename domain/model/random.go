@@ -1,21 +1,31 @@
 package model
 
 import (
+	"fmt"
+	"math"
 	"math/rand"
 	"strconv"
 	"strings"
 	"time"
 )
 
-var chars = []rune("")
-var lowerChars = []rune("abcdefghijklmnopqrstuvwxyz")
-var capitalChars = []rune("ABCDEFGHIJKLMNOPQRSTUVWXYZ")
-var numChars = []rune("0123456789")
+const lowerChars = "abcdefghijklmnopqrstuvwxyz"
+const capitalChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+const numChars = "0123456789"
+const chars = lowerChars + capitalChars + numChars
+
+// a random number of 63 bits gives the indexes of several chars, charIdxBits bits for each
+const (
+	charIdxBits  = 6
+	charIdxMask  = 1<<charIdxBits - 1
+	charIdxCount = 63 / charIdxBits
+)
 
 const layout = "2006-01-02 15:04:05"
+const dateLayout = "2006-01-02"
 
 //mysql int range
-var intRangeMap = map[ColumnTypeBase][]int{
+var intRangeMap = map[ColumnTypeBase][2]int64{
 	Tinyint:   {-128, 127},
 	Smallint:  {-32768, 32767},
 	Mediumint: {-8388608, 8388607},
@@ -23,54 +33,330 @@ var intRangeMap = map[ColumnTypeBase][]int{
 	Bigint:    {-9223372036854775808, 9223372036854775807},
 }
 
-func init() {
-	rand.Seed(time.Now().UnixNano())
-	chars = append(chars, lowerChars...)
-	chars = append(chars, capitalChars...)
-	chars = append(chars, numChars...)
+func generateRandomString(r *rand.Rand, n int) Value {
+	str := make([]byte, n)
+	for i, cache, remain := 0, r.Int63(), charIdxCount; i < n; {
+		if remain == 0 {
+			cache, remain = r.Int63(), charIdxCount
+		}
+		// indexes out of chars are skipped so that every char has the same probability
+		if idx := int(cache & charIdxMask); idx < len(chars) {
+			str[i] = chars[idx]
+			i++
+		}
+		cache >>= charIdxBits
+		remain--
+	}
+	return NewStringValue(string(str))
 }
 
-func generateRandomString(n int) string {
-	str := make([]rune, n)
-	for i := range str {
-		str[i] = chars[rand.Intn(len(chars))]
+// generateRandomBytes returns n bytes of any value, which are not always valid as a text
+func generateRandomBytes(r *rand.Rand, n int) Value {
+	b := make([]byte, n)
+	r.Read(b)
+	return NewBytesValue(b)
+}
+
+// generateRandomInt returns an integer in the whole range of the type, from 0 to 2^bits-1 if unsigned.
+// the range is computed in uint64, so that the range of bigint does not overflow.
+func generateRandomInt(r *rand.Rand, t ColumnTypeBase, unsigned bool) Value {
+	return intAt(t, unsigned, randUint64(r, intSpan(t)))
+}
+
+// intAt returns the integer at the offset from the min of the type, e.g. -128 at 0 and 127 at 255 for tinyint.
+func intAt(t ColumnTypeBase, unsigned bool, offset uint64) Value {
+	if unsigned {
+		return NewUintValue(offset)
+	}
+	return NewIntValue(int64(uint64(intRangeMap[t][0]) + offset))
+}
+
+// intSpan returns the offset of the max from the min of the type, e.g. 255 for tinyint, which is 2^64-1 for bigint.
+func intSpan(t ColumnTypeBase) uint64 {
+	return uint64(intRangeMap[t][1]) - uint64(intRangeMap[t][0])
+}
+
+// intDomainSize returns the # of the integers of the type, which is the same for signed and unsigned.
+func intDomainSize(t ColumnTypeBase) uint64 {
+	span := intSpan(t)
+	if span == math.MaxUint64 {
+		return math.MaxUint64
 	}
-	return string(str)
+	return span + 1
 }
 
-func generateRandomInt(t ColumnTypeBase, unsigned bool) string {
-	var m int
-	switch unsigned {
-	case true:
-		m = rand.Intn(intRangeMap[t][1])
-	case false:
-		m = rand.Intn(intRangeMap[t][1]-intRangeMap[t][0]) + intRangeMap[t][0]
+// randUint64 returns a random number from 0 to max inclusive, each of which has the same probability.
+func randUint64(r *rand.Rand, max uint64) uint64 {
+	if max == math.MaxUint64 {
+		return r.Uint64()
+	}
+	n := max + 1
+	// the numbers at or over limit are drawn again, because they do not make a whole round of n
+	rem := (math.MaxUint64%n + 1) % n
+	limit := -rem
+	for {
+		if u := r.Uint64(); rem == 0 || u < limit {
+			return u % n
+		}
 	}
-	return strconv.Itoa(m)
 }
 
-func generateRandomTinyint() string {
-	str := make([]rune, 1)
-	for i := range str {
-		str[i] = numChars[rand.Intn(len(numChars))%2]
+// generateRandomTinyint returns 0 or 1 for tinyint(1), which is a boolean like BOOL, and any value in the range for the other widths.
+func generateRandomTinyint(r *rand.Rand, width int, unsigned bool) Value {
+	if width == 1 {
+		return NewIntValue(int64(r.Intn(2)))
+	}
+	return generateRandomInt(r, Tinyint, unsigned)
+}
+
+// generateRandomBit returns a bit field of the width, e.g. 0101 for bit(4).
+func generateRandomBit(r *rand.Rand, width int) Value {
+	u := r.Uint64()
+	if width < MaxBitWidth {
+		u &= 1<<uint(width) - 1
+	}
+	return NewBitValue(fmt.Sprintf("%0*b", width, u))
+}
+
+// generateRandomDecimal returns a fixed-point number of the precision and the scale, e.g. -123.45 for decimal(5,2).
+// every digit is random, so that the values spread over the whole range of the type.
+func generateRandomDecimal(r *rand.Rand, precision, scale int, unsigned bool) Value {
+	if scale > precision {
+		scale = precision
+	}
+	digits := make([]byte, precision)
+	for i := range digits {
+		digits[i] = numChars[r.Intn(len(numChars))]
+	}
+	s := strings.TrimLeft(string(digits[:precision-scale]), "0")
+	if s == "" {
+		s = "0"
+	}
+	if scale > 0 {
+		s += "." + string(digits[precision-scale:])
+	}
+	// zero has no sign
+	if !unsigned && r.Intn(2) == 0 && strings.Trim(string(digits), "0") != "" {
+		s = "-" + s
+	}
+	return NewDecimalValue(s)
+}
+
+// the min and the max magnitude of FLOAT and DOUBLE in MySQL, other than 0
+var floatRangeMap = map[ColumnTypeBase][2]float64{
+	Float:  {1.175494351e-38, 3.402823466e+38},
+	Double: {2.2250738585072014e-308, math.MaxFloat64},
+}
+
+// floatExponentRange is the range of the exponents of the random floating-point numbers, e.g. from 1e-6 to 9.99e+6,
+// so that the values look like the usual ones rather than spreading over the whole range of the type
+const floatExponentRange = 6
+
+// generateRandomFloat returns a floating-point number of the type.
+// FLOAT(M,D) and DOUBLE(M,D) get the values fitting within M digits in total and D digits after the decimal point.
+func generateRandomFloat(r *rand.Rand, t ColumnType, unsigned bool) Value {
+	if t.Param > 0 {
+		d := generateRandomDecimal(r, int(t.Param), int(t.Scale), unsigned)
+		f, _ := strconv.ParseFloat(string(d.Data), 64)
+		if t.Base == Float {
+			return newFixedFloat32Value(f, int(t.Param), int(t.Scale))
+		}
+		return NewFloatValue(f)
+	}
+	f := (1 + r.Float64()*9) * math.Pow10(r.Intn(2*floatExponentRange+1)-floatExponentRange)
+	if !unsigned && r.Intn(2) == 0 {
+		f = -f
+	}
+	return newFloatValueOf(t.Base, f)
+}
+
+// generateSpecialFloat returns one of the values at the edges of the type, which are 0, -0, and the min and the max magnitude,
+// negative at random unless unsigned.
+// they are written in the digits of floatRangeMap even for FLOAT, because the shortest digits of float32 exceed the range of MySQL.
+func generateSpecialFloat(r *rand.Rand, t ColumnType, unsigned bool) Value {
+	min, max := floatRangeMap[t.Base][0], floatRangeMap[t.Base][1]
+	if t.Param > 0 {
+		digits, scale := int(t.Param), int(t.Scale)
+		min = math.Pow10(-scale)
+		max, _ = strconv.ParseFloat(strings.Repeat("9", digits-scale)+"."+strings.Repeat("9", scale), 64)
 	}
-	return string(str)
+	values := []float64{0, min, max}
+	if !unsigned {
+		values = append(values, math.Copysign(0, -1), -min, -max)
+	}
+	return NewFloatValue(values[r.Intn(len(values))])
+}
+
+// newFloatValueOf makes the value in the precision of the type, so that FLOAT is written in the digits of single precision.
+func newFloatValueOf(t ColumnTypeBase, f float64) Value {
+	if t == Float {
+		return NewFloat32Value(float32(f))
+	}
+	return NewFloatValue(f)
+}
+
+// newFixedFloat32Value makes the value of FLOAT(M,D) in single precision.
+// the nearest float32 can exceed the max of M digits for M > 7, e.g. 99999999.99 becomes 1e+08,
+// so that it is moved toward zero until the written digits rounded to D digits fit, as mysql checks them.
+// the range of FLOAT is checked as well, because the shortest digits of the max float32 exceed it.
+func newFixedFloat32Value(f float64, digits, scale int) Value {
+	limit := math.Pow10(digits) - 1
+	unit := math.Pow10(scale)
+	x := float32(f)
+	for {
+		v := NewFloat32Value(x)
+		g, _ := strconv.ParseFloat(string(v.Data), 64)
+		if math.Abs(math.Round(g*unit)) <= limit && math.Abs(g) <= floatRangeMap[Float][1] {
+			return v
+		}
+		x = math.Nextafter32(x, 0)
+	}
+}
+
+var minDate = time.Date(1971, 1, 0, 0, 0, 0, 0, time.UTC).Unix() //the min of timestamp in mysql is 1970-01-01
+var maxDate = time.Date(2037, 1, 0, 0, 0, 0, 0, time.UTC).Unix() //2038 problem for mysql timestamp
+
+// the ranges of the dates in mysql, from the min to the max (exclusive) in seconds from the Unix epoch
+var dateRangeMap = map[ColumnTypeBase][2]int64{
+	Timestamp: {minDate, maxDate},
+	Datetime:  {time.Date(1000, 1, 1, 0, 0, 0, 0, time.UTC).Unix(), time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC).Unix()},
+	Date:      {time.Date(1000, 1, 1, 0, 0, 0, 0, time.UTC).Unix(), time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC).Unix()},
 }
 
-func generateRandomDate() string {
-	min := time.Date(1971, 1, 0, 0, 0, 0, 0, time.UTC).Unix() //the min of timestamp in mysql is 1970-01-01
-	max := time.Date(2037, 1, 0, 0, 0, 0, 0, time.UTC).Unix() //2038 problem for mysql timestamp
-	delta := max - min
+// maxTime is the max of time in mysql, 838:59:59, in seconds. the min is -838:59:59.
+const maxTime = 838*60*60 + 59*60 + 59
+
+// the range of year in mysql, other than 0000
+const (
+	minYear = 1901
+	maxYear = 2155
+)
 
-	sec := rand.Int63n(delta) + min
-	return time.Unix(sec, 0).Format(layout)
+// generateRandomDate returns a date of the type, with the fractional seconds of fsp digits unless it is DATE.
+// it is in UTC, so that the same seed gives the same dates in any time zone.
+func generateRandomDate(r *rand.Rand, base ColumnTypeBase, fsp int) Value {
+	rng := dateRangeMap[base]
+	t := time.Unix(r.Int63n(rng[1]-rng[0])+rng[0], 0).UTC()
+	if base == Date {
+		return NewTimeValue(t.Format(dateLayout))
+	}
+	return NewTimeValue(t.Format(layout) + generateRandomFraction(r, fsp))
+}
+
+// generateRandomTime returns a time from -838:59:59 to 838:59:59, with the fractional seconds of fsp digits.
+func generateRandomTime(r *rand.Rand, fsp int) Value {
+	sec := r.Int63n(2*maxTime+1) - maxTime
+	frac := int64(0)
+	if fsp > 0 {
+		frac = r.Int63n(int64(powSaturated(10, fsp)))
+	}
+	return newTimeValueOf(sec, frac, fsp)
+}
+
+// newTimeValueOf writes the time of sec seconds with the fractional seconds frac of fsp digits, e.g. -12:34:56.789.
+// the fraction is 0 at the edges, because ±838:59:59 is the limit of TIME including the fractional seconds.
+func newTimeValueOf(sec, frac int64, fsp int) Value {
+	sign := ""
+	if sec < 0 {
+		sign, sec = "-", -sec
+	}
+	if sec >= maxTime {
+		frac = 0
+	}
+	text := fmt.Sprintf("%s%02d:%02d:%02d", sign, sec/3600, sec/60%60, sec%60)
+	if fsp > 0 {
+		text += fmt.Sprintf(".%0*d", fsp, frac)
+	}
+	return NewTimeValue(text)
+}
+
+// generateRandomFraction returns the fractional seconds of fsp digits with the leading dot, or "" for fsp 0.
+func generateRandomFraction(r *rand.Rand, fsp int) string {
+	if fsp <= 0 {
+		return ""
+	}
+	return fmt.Sprintf(".%0*d", fsp, r.Int63n(int64(powSaturated(10, fsp))))
+}
+
+func generateRandomYear(r *rand.Rand) Value {
+	return NewIntValue(int64(r.Intn(maxYear-minYear+1) + minYear))
 }
 
 // TODO: mod random data
-func generateRandomJson() string {
-	str := make([]rune, 10)
+func generateRandomJson(r *rand.Rand) Value {
+	str := make([]byte, 10)
 	for i := range str {
-		str[i] = numChars[rand.Intn(len(numChars))]
+		str[i] = numChars[r.Intn(len(numChars))]
+	}
+	return NewJSONValue(strings.Join([]string{`{"json":"`, string(str), `"}`}, ""))
+}
+
+// domainSize returns the # of distinct values which GenerateRandomData can generate for the column.
+// it saturates at math.MaxUint64.
+func (c Column) domainSize() uint64 {
+	switch c.Type.Base {
+	case Varchar, Char, Tinytext, Text, Mediumtext, Longtext:
+		if c.caseInsensitive() {
+			// the lower and the capital letters are the same in keys
+			return c.lengthDomainSize(uint64(len(lowerChars + numChars)))
+		}
+		return c.lengthDomainSize(uint64(len(chars)))
+	case Varbinary, Binary, Tinyblob, Blob, Mediumblob, Longblob:
+		return c.lengthDomainSize(256)
+	case Smallint, Mediumint, Int, Bigint:
+		return intDomainSize(c.Type.Base)
+	case Tinyint:
+		if c.Type.Param == 1 {
+			return 2
+		}
+		return intDomainSize(Tinyint)
+	case Bit:
+		return powSaturated(2, int(c.Type.Param))
+	case Timestamp, Datetime:
+		rng := dateRangeMap[c.Type.Base]
+		return mulSaturated(uint64(rng[1]-rng[0]), powSaturated(10, int(c.Type.Param)))
+	case Date:
+		rng := dateRangeMap[Date]
+		return uint64(rng[1]-rng[0]) / (24 * 60 * 60)
+	case Time:
+		return mulSaturated(2*maxTime+1, powSaturated(10, int(c.Type.Param)))
+	case Year:
+		return maxYear - minYear + 1
+	case Json:
+		return powSaturated(uint64(len(numChars)), 10)
+	case Decimal:
+		if c.Unsigned {
+			return powSaturated(uint64(len(numChars)), int(c.Type.Param))
+		}
+		return mulSaturated(powSaturated(uint64(len(numChars)), int(c.Type.Param)), 2) - 1
+	case Enum, Set:
+		return c.memberDomainSize()
+	case Geometry, Point, Linestring, Polygon, Multipoint, Multilinestring, Multipolygon, Geometrycollection:
+		return math.MaxUint64
+	case Float, Double:
+		if c.Type.Param > 0 {
+			return powSaturated(uint64(len(numChars)), int(c.Type.Param))
+		}
+		if c.Type.Base == Float {
+			return math.MaxUint32
+		}
+		return math.MaxUint64
+	default:
+		return math.MaxUint64
+	}
+}
+
+func mulSaturated(a, b uint64) uint64 {
+	if a != 0 && b > math.MaxUint64/a {
+		return math.MaxUint64
+	}
+	return a * b
+}
+
+func powSaturated(base uint64, exp int) uint64 {
+	re := uint64(1)
+	for i := 0; i < exp; i++ {
+		re = mulSaturated(re, base)
 	}
-	return strings.Join([]string{`{"json":"`, string(str), `"}`}, "")
+	return re
 }
@@ -0,0 +1,461 @@
+package model
+
+import (
+	"math"
+	"math/rand"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestColumn_GenerateRandomData(t *testing.T) {
+	type fields struct {
+		Type     ColumnType
+		Unsigned bool
+		Length   LengthRange
+	}
+	tests := []struct {
+		name     string
+		fields   fields
+		assertFn func(*testing.T, []Value)
+	}{
+		{
+			name:   "generate decimals fitting within the precision and the scale",
+			fields: fields{Type: ColumnType{Base: Decimal, Param: 5, Scale: 2}},
+			assertFn: func(t *testing.T, vs []Value) {
+				re := regexp.MustCompile(`^-?(0|[1-9][0-9]{0,2})\.[0-9]{2}$`)
+				negative := false
+				for _, v := range vs {
+					if v.Kind != DecimalKind || !re.MatchString(string(v.Data)) {
+						t.Fatalf("the value does not fit in decimal(5,2); %v", v)
+					}
+					negative = negative || v.Data[0] == '-'
+				}
+				if !negative {
+					t.Errorf("no negative value is generated")
+				}
+			},
+		},
+		{
+			name:   "generate non-negative integral decimals for unsigned decimal(3,0)",
+			fields: fields{Type: ColumnType{Base: Decimal, Param: 3}, Unsigned: true},
+			assertFn: func(t *testing.T, vs []Value) {
+				re := regexp.MustCompile(`^(0|[1-9][0-9]{0,2})$`)
+				for _, v := range vs {
+					if !re.MatchString(string(v.Data)) {
+						t.Fatalf("the value does not fit in unsigned decimal(3,0); %v", v)
+					}
+				}
+			},
+		},
+		{
+			name:   "generate decimals with only the fractional part for decimal(4,4)",
+			fields: fields{Type: ColumnType{Base: Decimal, Param: 4, Scale: 4}},
+			assertFn: func(t *testing.T, vs []Value) {
+				re := regexp.MustCompile(`^-?0\.[0-9]{4}$`)
+				for _, v := range vs {
+					if !re.MatchString(string(v.Data)) {
+						t.Fatalf("the value does not fit in decimal(4,4); %v", v)
+					}
+				}
+			},
+		},
+		{
+			name:   "generate strings of every length within the range",
+			fields: fields{Type: ColumnType{Base: Mediumtext, Param: 16777215}, Length: LengthRange{Min: 2, Max: 5}},
+			assertFn: func(t *testing.T, vs []Value) {
+				lengths := map[int]bool{}
+				for _, v := range vs {
+					if v.Kind != StringKind || len(v.Data) < 2 || len(v.Data) > 5 {
+						t.Fatalf("the length of the value is out of the range; %v", v)
+					}
+					lengths[len(v.Data)] = true
+				}
+				if diff := cmp.Diff(lengths, map[int]bool{2: true, 3: true, 4: true, 5: true}); diff != "" {
+					t.Errorf("the lengths; -got, +want\n%v", diff)
+				}
+			},
+		},
+		{
+			name:   "generate bytes of the fixed length of binary(16)",
+			fields: fields{Type: ColumnType{Base: Binary, Param: 16}, Length: LengthRange{Min: 1, Max: 4}},
+			assertFn: func(t *testing.T, vs []Value) {
+				for _, v := range vs {
+					if v.Kind != BytesKind || len(v.Data) != 16 {
+						t.Fatalf("the value is not of binary(16); %v", v)
+					}
+				}
+			},
+		},
+		{
+			name:   "generate booleans for tinyint(1)",
+			fields: fields{Type: ColumnType{Base: Tinyint, Param: 1}},
+			assertFn: func(t *testing.T, vs []Value) {
+				for _, v := range vs {
+					if v.Data != "0" && v.Data != "1" {
+						t.Fatalf("the value is not a boolean; %v", v)
+					}
+				}
+			},
+		},
+		{
+			name:   "generate values over the whole range of tinyint(4)",
+			fields: fields{Type: ColumnType{Base: Tinyint, Param: 4}},
+			assertFn: func(t *testing.T, vs []Value) {
+				min, max := 0, 0
+				for _, v := range vs {
+					i, _ := strconv.Atoi(string(v.Data))
+					if i < -128 || i > 127 {
+						t.Fatalf("the value is out of the range of tinyint; %v", v)
+					}
+					if i < min {
+						min = i
+					}
+					if i > max {
+						max = i
+					}
+				}
+				if min > -100 || max < 100 {
+					t.Errorf("the values range from %d to %d, want about -128 to 127", min, max)
+				}
+			},
+		},
+		{
+			name:   "generate values over the whole range of unsigned tinyint",
+			fields: fields{Type: ColumnType{Base: Tinyint}, Unsigned: true},
+			assertFn: func(t *testing.T, vs []Value) {
+				max := 0
+				for _, v := range vs {
+					i, _ := strconv.Atoi(string(v.Data))
+					if i < 0 || i > 255 {
+						t.Fatalf("the value is out of the range of unsigned tinyint; %v", v)
+					}
+					if i > max {
+						max = i
+					}
+				}
+				if max < 200 {
+					t.Errorf("the max of the values is %d, want about 255", max)
+				}
+			},
+		},
+		{
+			name:   "generate values over the whole range of unsigned bigint",
+			fields: fields{Type: ColumnType{Base: Bigint}, Unsigned: true},
+			assertFn: func(t *testing.T, vs []Value) {
+				overSigned := false
+				for _, v := range vs {
+					u, err := strconv.ParseUint(string(v.Data), 10, 64)
+					if err != nil {
+						t.Fatalf("the value is not an unsigned bigint; %v", v)
+					}
+					overSigned = overSigned || u > math.MaxInt64
+				}
+				if !overSigned {
+					t.Errorf("no value over the max of signed bigint is generated")
+				}
+			},
+		},
+		{
+			name:   "generate negative and positive values of bigint",
+			fields: fields{Type: ColumnType{Base: Bigint}},
+			assertFn: func(t *testing.T, vs []Value) {
+				negative, large := false, false
+				for _, v := range vs {
+					i, err := strconv.ParseInt(string(v.Data), 10, 64)
+					if err != nil {
+						t.Fatalf("the value is not a bigint; %v", v)
+					}
+					negative = negative || i < 0
+					large = large || i > math.MaxInt32
+				}
+				if !negative || !large {
+					t.Errorf("negative = %v, over the range of int = %v, want both", negative, large)
+				}
+			},
+		},
+		{
+			name:   "generate bit fields of the width of bit(5)",
+			fields: fields{Type: ColumnType{Base: Bit, Param: 5}},
+			assertFn: func(t *testing.T, vs []Value) {
+				re := regexp.MustCompile(`^[01]{5}$`)
+				for _, v := range vs {
+					if v.Kind != BitKind || !re.MatchString(string(v.Data)) {
+						t.Fatalf("the value is not of bit(5); %v", v)
+					}
+				}
+			},
+		},
+		{
+			name:   "generate floats fitting within the digits and the scale of float(7,2)",
+			fields: fields{Type: ColumnType{Base: Float, Param: 7, Scale: 2}},
+			assertFn: func(t *testing.T, vs []Value) {
+				for _, v := range vs {
+					f, err := strconv.ParseFloat(string(v.Data), 64)
+					if v.Kind != FloatKind || err != nil || math.Abs(f) >= 100000 || math.Abs(math.Round(f*100)-f*100) > 1e-6 {
+						t.Fatalf("the value does not fit in float(7,2); %v", v)
+					}
+				}
+			},
+		},
+		{
+			name:   "generate floats in the digits of single precision",
+			fields: fields{Type: ColumnType{Base: Float}},
+			assertFn: func(t *testing.T, vs []Value) {
+				negative := false
+				for _, v := range vs {
+					f, err := strconv.ParseFloat(string(v.Data), 32)
+					if err != nil || strconv.FormatFloat(f, 'g', -1, 32) != string(v.Data) {
+						t.Fatalf("the value is not a float of single precision; %v", v)
+					}
+					negative = negative || f < 0
+				}
+				if !negative {
+					t.Errorf("no negative value is generated")
+				}
+			},
+		},
+		{
+			name:   "generate non-negative doubles for unsigned double",
+			fields: fields{Type: ColumnType{Base: Double}, Unsigned: true},
+			assertFn: func(t *testing.T, vs []Value) {
+				for _, v := range vs {
+					f, err := strconv.ParseFloat(string(v.Data), 64)
+					if err != nil || f < 0 {
+						t.Fatalf("the value is not a non-negative double; %v", v)
+					}
+				}
+			},
+		},
+		{
+			name:   "generate datetimes with the fractional seconds of datetime(6)",
+			fields: fields{Type: ColumnType{Base: Datetime, Param: 6}},
+			assertFn: func(t *testing.T, vs []Value) {
+				for _, v := range vs {
+					if _, err := time.Parse("2006-01-02 15:04:05.000000", string(v.Data)); v.Kind != TimeKind || err != nil {
+						t.Fatalf("the value is not a datetime(6); %v", v)
+					}
+				}
+			},
+		},
+		{
+			name:   "generate dates from 1000-01-01 to 9999-12-31",
+			fields: fields{Type: ColumnType{Base: Date}},
+			assertFn: func(t *testing.T, vs []Value) {
+				beforeEpoch := false
+				for _, v := range vs {
+					d, err := time.Parse("2006-01-02", string(v.Data))
+					if err != nil || d.Year() < 1000 || d.Year() > 9999 {
+						t.Fatalf("the value is not a date of mysql; %v", v)
+					}
+					beforeEpoch = beforeEpoch || d.Year() < 1970
+				}
+				if !beforeEpoch {
+					t.Errorf("no date before 1970 is generated")
+				}
+			},
+		},
+		{
+			name:   "generate times from -838:59:59 to 838:59:59 with the fractional seconds of time(2)",
+			fields: fields{Type: ColumnType{Base: Time, Param: 2}},
+			assertFn: func(t *testing.T, vs []Value) {
+				re := regexp.MustCompile(`^(-?)([0-9]{2,3}):([0-5][0-9]):([0-5][0-9])\.[0-9]{2}$`)
+				negative, over24 := false, false
+				for _, v := range vs {
+					m := re.FindStringSubmatch(string(v.Data))
+					if v.Kind != TimeKind || m == nil {
+						t.Fatalf("the value is not a time(2); %v", v)
+					}
+					h, _ := strconv.Atoi(m[2])
+					if h > 838 {
+						t.Fatalf("the value is out of the range of time; %v", v)
+					}
+					negative = negative || m[1] == "-"
+					over24 = over24 || h >= 24
+				}
+				if !negative || !over24 {
+					t.Errorf("negative = %v, over 24 hours = %v, want both", negative, over24)
+				}
+			},
+		},
+		{
+			name:   "generate years from 1901 to 2155",
+			fields: fields{Type: ColumnType{Base: Year}},
+			assertFn: func(t *testing.T, vs []Value) {
+				for _, v := range vs {
+					y, err := strconv.Atoi(string(v.Data))
+					if v.Kind != IntKind || err != nil || y < 1901 || y > 2155 {
+						t.Fatalf("the value is not a year of mysql; %v", v)
+					}
+				}
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := Column{Type: tt.fields.Type, Unsigned: tt.fields.Unsigned, Length: tt.fields.Length}
+			r := rand.New(rand.NewSource(1))
+			vs := make([]Value, 0, 1000)
+			for i := 0; i < 1000; i++ {
+				vs = append(vs, c.GenerateRandomData(r))
+			}
+			tt.assertFn(t, vs)
+		})
+	}
+}
+
+func Test_generateSpecialFloat(t *testing.T) {
+	type args struct {
+		t        ColumnType
+		unsigned bool
+	}
+	tests := []struct {
+		name string
+		args args
+		want map[string]bool
+	}{
+		{
+			name: "generate 0, -0 and the min and the max magnitude of float",
+			args: args{t: ColumnType{Base: Float}},
+			want: map[string]bool{"0": true, "-0e0": true, "1.175494351e-38": true, "-1.175494351e-38": true, "3.402823466e+38": true, "-3.402823466e+38": true},
+		},
+		{
+			name: "generate non-negative special values of unsigned double",
+			args: args{t: ColumnType{Base: Double}, unsigned: true},
+			want: map[string]bool{"0": true, "2.2250738585072014e-308": true, "1.7976931348623157e+308": true},
+		},
+		{
+			name: "generate the min and the max magnitude within the digits and the scale of double(5,2)",
+			args: args{t: ColumnType{Base: Double, Param: 5, Scale: 2}},
+			want: map[string]bool{"0": true, "-0e0": true, "0.01": true, "-0.01": true, "999.99": true, "-999.99": true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			got := map[string]bool{}
+			for i := 0; i < 100; i++ {
+				// the SQL literals, where -0 has to be a float literal to keep the sign
+				got[generateSpecialFloat(r, tt.args.t, tt.args.unsigned).SQL(MySQL)] = true
+			}
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("generateSpecialFloat(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
+
+func Test_intAt(t *testing.T) {
+	type args struct {
+		t        ColumnTypeBase
+		unsigned bool
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantMin string
+		wantMax string
+	}{
+		{name: "tinyint", args: args{t: Tinyint}, wantMin: "-128", wantMax: "127"},
+		{name: "unsigned tinyint", args: args{t: Tinyint, unsigned: true}, wantMin: "0", wantMax: "255"},
+		{name: "smallint", args: args{t: Smallint}, wantMin: "-32768", wantMax: "32767"},
+		{name: "unsigned smallint", args: args{t: Smallint, unsigned: true}, wantMin: "0", wantMax: "65535"},
+		{name: "mediumint", args: args{t: Mediumint}, wantMin: "-8388608", wantMax: "8388607"},
+		{name: "unsigned mediumint", args: args{t: Mediumint, unsigned: true}, wantMin: "0", wantMax: "16777215"},
+		{name: "int", args: args{t: Int}, wantMin: "-2147483648", wantMax: "2147483647"},
+		{name: "unsigned int", args: args{t: Int, unsigned: true}, wantMin: "0", wantMax: "4294967295"},
+		{name: "bigint", args: args{t: Bigint}, wantMin: "-9223372036854775808", wantMax: "9223372036854775807"},
+		{name: "unsigned bigint", args: args{t: Bigint, unsigned: true}, wantMin: "0", wantMax: "18446744073709551615"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := [2]string{
+				string(intAt(tt.args.t, tt.args.unsigned, 0).Data),
+				string(intAt(tt.args.t, tt.args.unsigned, intSpan(tt.args.t)).Data),
+			}
+			diff := cmp.Diff(got, [2]string{tt.wantMin, tt.wantMax})
+			if diff != "" {
+				t.Errorf("intAt(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
+
+func Test_newTimeValueOf(t *testing.T) {
+	type args struct {
+		sec  int64
+		frac int64
+		fsp  int
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{name: "without fractional seconds", args: args{sec: -3723}, want: "-01:02:03"},
+		{name: "with fractional seconds", args: args{sec: 45296, frac: 789, fsp: 3}, want: "12:34:56.789"},
+		{name: "fraction below the max", args: args{sec: maxTime - 1, frac: 5, fsp: 1}, want: "838:59:58.5"},
+		{name: "no fraction beyond the max", args: args{sec: maxTime, frac: 5, fsp: 1}, want: "838:59:59.0"},
+		{name: "no fraction beyond the min", args: args{sec: -maxTime, frac: 999999, fsp: 6}, want: "-838:59:59.000000"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(newTimeValueOf(tt.args.sec, tt.args.frac, tt.args.fsp).Data)
+			if got != tt.want {
+				t.Errorf("newTimeValueOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_newFixedFloat32Value(t *testing.T) {
+	type args struct {
+		f      float64
+		digits int
+		scale  int
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{name: "value fitting in single precision", args: args{f: 12345.67, digits: 7, scale: 2}, want: "12345.67"},
+		{name: "max of float(7,2)", args: args{f: 99999.99, digits: 7, scale: 2}, want: "99999.99"},
+		{name: "max of float(10,2) rounded up by single precision", args: args{f: 99999999.99, digits: 10, scale: 2}, want: "9.999999e+07"},
+		{name: "min of float(10,2) rounded down by single precision", args: args{f: -99999999.99, digits: 10, scale: 2}, want: "-9.999999e+07"},
+		{name: "max of float(12,0) rounded up by single precision", args: args{f: 999999999999, digits: 12, scale: 0}, want: "9.999999e+11"},
+		{name: "beyond the range of single precision", args: args{f: 1e40, digits: 45, scale: 0}, want: "3.4028233e+38"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := string(newFixedFloat32Value(tt.args.f, tt.args.digits, tt.args.scale).Data)
+			if got != tt.want {
+				t.Errorf("newFixedFloat32Value() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_randUint64(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	counts := make([]int, 3)
+	for i := 0; i < 3000; i++ {
+		u := randUint64(r, 2)
+		if u > 2 {
+			t.Fatalf("randUint64() = %d, want from 0 to 2", u)
+		}
+		counts[u]++
+	}
+	for u, c := range counts {
+		if c < 900 || c > 1100 {
+			t.Errorf("randUint64() gave %d %d times out of 3000, want about 1000", u, c)
+		}
+	}
+	if u := randUint64(r, 0); u != 0 {
+		t.Errorf("randUint64() = %d, want 0", u)
+	}
+}
@@ -1,5 +1,3 @@
 package model
 
-type Value string
-
 type Record []Value
@@ -0,0 +1,72 @@
+package model
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// RecordNumber is the # of records to generate for each table
+type RecordNumber struct {
+	// Default is used for the tables which are not in Tables
+	Default int
+	Tables  map[TableName]int
+}
+
+func NewRecordNumber(n int) RecordNumber {
+	return RecordNumber{
+		Default: n,
+		Tables:  map[TableName]int{},
+	}
+}
+
+// ParseRecordNumber converts strings such as "10", "orders=100000,country=50" and "10,country=50" into RecordNumber.
+// a number without table name is the default, and tables not listed get defaultNum when the default is not given.
+func ParseRecordNumber(str string, defaultNum int) (RecordNumber, error) {
+	rn := NewRecordNumber(defaultNum)
+	for _, item := range strings.Split(str, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		tn, num, hasTableName := strings.Cut(item, "=")
+		if !hasTableName {
+			tn, num = "", tn
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(num))
+		if err != nil || n < 1 {
+			return RecordNumber{}, errors.Errorf("invalid record number %q, it must be a positive number", item)
+		}
+		if hasTableName {
+			rn.Tables[TableName(strings.TrimSpace(tn))] = n
+		} else {
+			rn.Default = n
+		}
+	}
+	return rn, nil
+}
+
+// Of returns the # of records for the table.
+func (rn RecordNumber) Of(tn TableName) int {
+	if n, ok := lookupByName(rn.Tables, tn); ok {
+		return n
+	}
+	return rn.Default
+}
+
+// Validate checks that every table given its own # of records exists in the schema.
+func (rn RecordNumber) Validate(schema Schema) error {
+	for tn := range rn.Tables {
+		found := false
+		for _, table := range schema.Tables {
+			if strings.EqualFold(string(table.Name), string(tn)) {
+				found = true
+			}
+		}
+		if !found {
+			return errors.Errorf("table %s is given the # of records but not found in the schema", tn)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRecordNumber(t *testing.T) {
+	type args struct {
+		str        string
+		defaultNum int
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    RecordNumber
+		wantErr bool
+	}{
+		{
+			name: "parse the # of records for all tables",
+			args: args{str: "100", defaultNum: 10},
+			want: RecordNumber{Default: 100, Tables: map[TableName]int{}},
+		},
+		{
+			name: "parse the # of records for each table, keeping the given default",
+			args: args{str: "orders=100000, country=50", defaultNum: 10},
+			want: RecordNumber{Default: 10, Tables: map[TableName]int{"orders": 100000, "country": 50}},
+		},
+		{
+			name: "parse both of the default and the # of records for each table",
+			args: args{str: "country=50,1000", defaultNum: 10},
+			want: RecordNumber{Default: 1000, Tables: map[TableName]int{"country": 50}},
+		},
+		{
+			name:    "return error for non-numeric value",
+			args:    args{str: "orders=many", defaultNum: 10},
+			wantErr: true,
+		},
+		{
+			name:    "return error for non-positive value",
+			args:    args{str: "0", defaultNum: 10},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseRecordNumber(tt.args.str, tt.args.defaultNum)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseRecordNumber() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("ParseRecordNumber(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestRecordNumber_Of(t *testing.T) {
+	rn := RecordNumber{Default: 10, Tables: map[TableName]int{"country": 50}}
+	tests := []struct {
+		name string
+		tn   TableName
+		want int
+	}{
+		{name: "return the # of records for the table", tn: "country", want: 50},
+		{name: "compare table names case-insensitively", tn: "Country", want: 50},
+		{name: "return the default for the other tables", tn: "orders", want: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := rn.Of(tt.tn); got != tt.want {
+				t.Errorf("RecordNumber.Of() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRecordNumber_Validate(t *testing.T) {
+	schema := Schema{Tables: []Table{{Name: "country"}, {Name: "orders"}}}
+	tests := []struct {
+		name    string
+		rn      RecordNumber
+		wantErr bool
+	}{
+		{
+			name: "accept tables in the schema",
+			rn:   RecordNumber{Default: 10, Tables: map[TableName]int{"COUNTRY": 50, "orders": 100}},
+		},
+		{
+			name:    "return error for tables not in the schema",
+			rn:      RecordNumber{Default: 10, Tables: map[TableName]int{"order": 100}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.rn.Validate(schema); (err != nil) != tt.wantErr {
+				t.Errorf("RecordNumber.Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
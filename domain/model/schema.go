@@ -1,5 +1,11 @@
 package model
 
+import (
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
 type Schema struct {
 	Tables []Table
 }
@@ -11,3 +17,91 @@ func (s *Schema) AddTable(table Table) {
 func (s *Schema) LastTable() *Table {
 	return &s.Tables[len(s.Tables)-1]
 }
+
+func (s Schema) column(tn TableName, cn ColumnName) (Column, bool) {
+	for _, t := range s.Tables {
+		if t.Name == tn {
+			return t.Column(cn)
+		}
+	}
+	return Column{}, false
+}
+
+// table returns the table of the name, or the table of the name in another case if no table has the same name,
+// because the names of tables are case-insensitive in MySQL on some platforms.
+func (s Schema) table(tn TableName) (Table, bool) {
+	for _, t := range s.Tables {
+		if t.Name == tn {
+			return t, true
+		}
+	}
+	for _, t := range s.Tables {
+		if strings.EqualFold(string(t.Name), string(tn)) {
+			return t, true
+		}
+	}
+	return Table{}, false
+}
+
+// resolveForeignKeys returns the schema whose foreign keys have the names of the tables and the columns as defined,
+// comparing the names of the columns case-insensitively like MySQL.
+// it returns error for a foreign key to a table or a column which is not defined, or to a generated column,
+// whose values are computed by the database and cannot be known for the child rows.
+func (s Schema) resolveForeignKeys() (Schema, error) {
+	resolved := Schema{Tables: make([]Table, 0, len(s.Tables))}
+	for _, table := range s.Tables {
+		t := table
+		t.Columns = make([]Column, 0, len(table.Columns))
+		for _, c := range table.Columns {
+			constraints := make([]Constraint, 0, len(c.Constraints))
+			for _, constraint := range c.Constraints {
+				parent, ok := s.table(constraint.TableName)
+				if !ok {
+					return Schema{}, errors.Errorf("the foreign key of %s refers to table %s, which is not defined", c.FullName, constraint.TableName)
+				}
+				referenced, err := resolveColumnNames(parent, []ColumnName{constraint.ColumnName})
+				if err != nil {
+					return Schema{}, errors.Wrapf(err, "the foreign key of %s", c.FullName)
+				}
+				constraint.TableName, constraint.ColumnName = parent.Name, referenced[0]
+				if constraint.IsComposite() {
+					if constraint.Columns, err = resolveColumnNames(table, constraint.Columns); err != nil {
+						return Schema{}, errors.Wrapf(err, "the foreign key of %s", c.FullName)
+					}
+					if constraint.ReferencedColumns, err = resolveColumnNames(parent, constraint.ReferencedColumns); err != nil {
+						return Schema{}, errors.Wrapf(err, "the foreign key of %s", c.FullName)
+					}
+				}
+				if pc, _ := parent.Column(constraint.ColumnName); pc.Generated {
+					return Schema{}, errors.Errorf("cannot generate the values of %s referring to %s, because it is a generated column", c.FullName, pc.FullName)
+				}
+				constraints = append(constraints, constraint)
+			}
+			if c.Constraints != nil {
+				c.Constraints = constraints
+			}
+			t.Columns = append(t.Columns, c)
+		}
+		resolved.Tables = append(resolved.Tables, t)
+	}
+	return resolved, nil
+}
+
+// resolveColumnNames returns the names of the columns as defined in the table, comparing them case-insensitively.
+func resolveColumnNames(table Table, names []ColumnName) ([]ColumnName, error) {
+	resolved := make([]ColumnName, 0, len(names))
+	for _, cn := range names {
+		found := false
+		for _, c := range table.Columns {
+			if strings.EqualFold(string(c.Name), string(cn)) {
+				resolved = append(resolved, c.Name)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, errors.Errorf("refers to column %s of table %s, which is not defined", cn, table.Name)
+		}
+	}
+	return resolved, nil
+}
@@ -0,0 +1,164 @@
+package model
+
+import (
+	"math/rand"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// BoundingBox is the area where the coordinates of spatial values are, e.g. 139.56,35.53,139.92,35.82 for the Tokyo area.
+// X is the longitude and Y is the latitude for geographic SRIDs such as 4326.
+// the zero value means DefaultBoundingBox.
+type BoundingBox struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+// DefaultBoundingBox is the whole world in longitude and latitude, which is valid for any SRID
+var DefaultBoundingBox = BoundingBox{MinX: -180, MinY: -90, MaxX: 180, MaxY: 90}
+
+// the # of the points of LINESTRING and the # of the elements of the MULTI types and GEOMETRYCOLLECTION
+const (
+	minSpatialElements = 2
+	maxSpatialElements = 4
+)
+
+// ParseBoundingBox converts strings such as "139.56,35.53,139.92,35.82" (min x, min y, max x, max y) into BoundingBox.
+func ParseBoundingBox(str string) (BoundingBox, error) {
+	parts := strings.Split(str, ",")
+	if len(parts) != 4 {
+		return BoundingBox{}, errors.Errorf("bounding box %q needs 4 numbers of min x, min y, max x and max y, e.g. 139.56,35.53,139.92,35.82", str)
+	}
+	coords := make([]float64, len(parts))
+	for k, part := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(part), 64)
+		if err != nil {
+			return BoundingBox{}, errors.Errorf("bounding box %q has %q, which is not a number", str, part)
+		}
+		coords[k] = f
+	}
+	bb := BoundingBox{MinX: coords[0], MinY: coords[1], MaxX: coords[2], MaxY: coords[3]}
+	if bb.MinX >= bb.MaxX || bb.MinY >= bb.MaxY {
+		return BoundingBox{}, errors.Errorf("bounding box %q needs the min less than the max for both x and y", str)
+	}
+	return bb, nil
+}
+
+func (bb BoundingBox) orDefault() BoundingBox {
+	if bb == (BoundingBox{}) {
+		return DefaultBoundingBox
+	}
+	return bb
+}
+
+// spatialGenerator writes random geometries in WKT within the bounding box.
+type spatialGenerator struct {
+	r  *rand.Rand
+	bb BoundingBox
+}
+
+// generateRandomGeometry returns a geometry of the type within the bounding box, e.g. POINT(139.7 35.6).
+// GEOMETRY is one of POINT, LINESTRING and POLYGON at random.
+func generateRandomGeometry(r *rand.Rand, t ColumnTypeBase, srid int, bb BoundingBox) Value {
+	g := spatialGenerator{r: r, bb: bb.orDefault()}
+	if t == Geometry {
+		t = []ColumnTypeBase{Point, Linestring, Polygon}[r.Intn(3)]
+	}
+	return NewGeometryValue(srid, g.wkt(t))
+}
+
+func (g spatialGenerator) wkt(t ColumnTypeBase) string {
+	switch t {
+	case Point:
+		return "POINT(" + g.point(g.bb) + ")"
+	case Linestring:
+		return "LINESTRING" + g.lineString()
+	case Polygon:
+		return "POLYGON" + g.polygon(g.bb)
+	case Multipoint:
+		points := make([]string, g.count())
+		for k := range points {
+			points[k] = "(" + g.point(g.bb) + ")"
+		}
+		return "MULTIPOINT(" + strings.Join(points, ",") + ")"
+	case Multilinestring:
+		lines := make([]string, g.count())
+		for k := range lines {
+			lines[k] = g.lineString()
+		}
+		return "MULTILINESTRING(" + strings.Join(lines, ",") + ")"
+	case Multipolygon:
+		// the polygons are in separate strips of the box, so that they never overlap
+		n := g.count()
+		polygons := make([]string, n)
+		width := (g.bb.MaxX - g.bb.MinX) / float64(n)
+		for k := range polygons {
+			strip := g.bb
+			strip.MinX = g.bb.MinX + width*float64(k)
+			strip.MaxX = strip.MinX + width
+			polygons[k] = g.polygon(strip)
+		}
+		return "MULTIPOLYGON(" + strings.Join(polygons, ",") + ")"
+	case Geometrycollection:
+		geometries := make([]string, g.count())
+		for k := range geometries {
+			geometries[k] = g.wkt([]ColumnTypeBase{Point, Linestring, Polygon}[g.r.Intn(3)])
+		}
+		return "GEOMETRYCOLLECTION(" + strings.Join(geometries, ",") + ")"
+	default:
+		return "POINT(" + g.point(g.bb) + ")"
+	}
+}
+
+func (g spatialGenerator) count() int {
+	return minSpatialElements + g.r.Intn(maxSpatialElements-minSpatialElements+1)
+}
+
+// point returns the coordinates of a point in the box, e.g. 139.7 35.6.
+func (g spatialGenerator) point(bb BoundingBox) string {
+	return coordinate(g.between(bb.MinX, bb.MaxX)) + " " + coordinate(g.between(bb.MinY, bb.MaxY))
+}
+
+func (g spatialGenerator) lineString() string {
+	points := make([]string, g.count())
+	for k := range points {
+		points[k] = g.point(g.bb)
+	}
+	return "(" + strings.Join(points, ",") + ")"
+}
+
+// polygon returns a rectangle in the box, whose ring is closed and counterclockwise.
+func (g spatialGenerator) polygon(bb BoundingBox) string {
+	x1, x2 := g.between(bb.MinX, bb.MaxX), g.between(bb.MinX, bb.MaxX)
+	y1, y2 := g.between(bb.MinY, bb.MaxY), g.between(bb.MinY, bb.MaxY)
+	if x1 > x2 {
+		x1, x2 = x2, x1
+	}
+	if y1 > y2 {
+		y1, y2 = y2, y1
+	}
+	// the coordinates are rounded in WKT, so the rectangle must not be thinner than the precision.
+	// the middle of the box is used instead, which never touches the edges of the box
+	if x2-x1 < 1e-5 || y2-y1 < 1e-5 {
+		w, h := bb.MaxX-bb.MinX, bb.MaxY-bb.MinY
+		x1, x2, y1, y2 = bb.MinX+w/4, bb.MaxX-w/4, bb.MinY+h/4, bb.MaxY-h/4
+	}
+	ring := []string{
+		coordinate(x1) + " " + coordinate(y1),
+		coordinate(x2) + " " + coordinate(y1),
+		coordinate(x2) + " " + coordinate(y2),
+		coordinate(x1) + " " + coordinate(y2),
+		coordinate(x1) + " " + coordinate(y1),
+	}
+	return "((" + strings.Join(ring, ",") + "))"
+}
+
+func (g spatialGenerator) between(min, max float64) float64 {
+	return min + g.r.Float64()*(max-min)
+}
+
+// coordinate writes a coordinate with 6 digits after the decimal point, which is about 0.1m in longitude and latitude.
+func coordinate(f float64) string {
+	return strconv.FormatFloat(f, 'f', 6, 64)
+}
@@ -0,0 +1,124 @@
+package model
+
+import (
+	"math/rand"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseBoundingBox(t *testing.T) {
+	type args struct {
+		str string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    BoundingBox
+		wantErr bool
+	}{
+		{
+			name: "parse min x, min y, max x and max y",
+			args: args{str: "139.56, 35.53, 139.92, 35.82"},
+			want: BoundingBox{MinX: 139.56, MinY: 35.53, MaxX: 139.92, MaxY: 35.82},
+		},
+		{
+			name:    "return error for the min greater than the max",
+			args:    args{str: "139.92,35.53,139.56,35.82"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for less than 4 numbers",
+			args:    args{str: "139.56,35.53"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for non-numeric coordinates",
+			args:    args{str: "a,b,c,d"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseBoundingBox(tt.args.str)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseBoundingBox() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("ParseBoundingBox(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
+
+func Test_generateRandomGeometry(t *testing.T) {
+	tokyo := BoundingBox{MinX: 139.56, MinY: 35.53, MaxX: 139.92, MaxY: 35.82}
+	coordinates := regexp.MustCompile(`(-?[0-9]+\.[0-9]{6}) (-?[0-9]+\.[0-9]{6})`)
+	type args struct {
+		t    ColumnTypeBase
+		srid int
+		bb   BoundingBox
+	}
+	tests := []struct {
+		name string
+		args args
+		// want matches the WKT of the values
+		want *regexp.Regexp
+	}{
+		{
+			name: "generate points in the bounding box with the SRID",
+			args: args{t: Point, srid: 4326, bb: tokyo},
+			want: regexp.MustCompile(`^SRID=4326;POINT\([0-9. ]+\)$`),
+		},
+		{
+			name: "generate line strings of several points",
+			args: args{t: Linestring, bb: tokyo},
+			want: regexp.MustCompile(`^LINESTRING\([0-9. ]+(,[0-9. ]+)+\)$`),
+		},
+		{
+			name: "generate polygons of closed rings",
+			args: args{t: Polygon, bb: tokyo},
+			want: regexp.MustCompile(`^POLYGON\(\(([0-9.]+ [0-9.]+),[0-9. ]+,[0-9. ]+,[0-9. ]+,([0-9.]+ [0-9.]+)\)\)$`),
+		},
+		{
+			name: "generate multi polygons in the bounding box",
+			args: args{t: Multipolygon, bb: tokyo},
+			want: regexp.MustCompile(`^MULTIPOLYGON\(\(\([0-9., ]+\)\)(,\(\([0-9., ]+\)\))+\)$`),
+		},
+		{
+			name: "generate geometry collections in the whole world by default",
+			args: args{t: Geometrycollection},
+			want: regexp.MustCompile(`^GEOMETRYCOLLECTION\((POINT|LINESTRING|POLYGON)\(.+\)\)$`),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := rand.New(rand.NewSource(1))
+			bb := tt.args.bb.orDefault()
+			for i := 0; i < 100; i++ {
+				v := generateRandomGeometry(r, tt.args.t, tt.args.srid, tt.args.bb)
+				if v.Kind != GeometryKind || !tt.want.MatchString(string(v.Data)) {
+					t.Fatalf("unexpected geometry %v", v)
+				}
+				if m := tt.want.FindStringSubmatch(string(v.Data)); tt.args.t == Polygon && m[1] != m[2] {
+					t.Fatalf("the ring is not closed; %v", v)
+				}
+				for _, c := range coordinates.FindAllStringSubmatch(string(v.Data), -1) {
+					x, _ := strconv.ParseFloat(c[1], 64)
+					y, _ := strconv.ParseFloat(c[2], 64)
+					if x < bb.MinX || x > bb.MaxX || y < bb.MinY || y > bb.MaxY {
+						t.Fatalf("the point %s is out of the bounding box; %v", c[0], v)
+					}
+				}
+				if strings.Contains(string(v.Data), "e") {
+					t.Fatalf("the coordinates are in exponent notation; %v", v)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,566 @@
+package model
+
+import (
+	"io"
+	"math"
+	"math/rand"
+
+	"github.com/pkg/errors"
+)
+
+// chunkSize is the # of records generated before they are written at once
+const chunkSize = 1000
+
+// WriteDummyData generates the records of all the tables and writes them to w in the format of the option.
+// the records are generated and written chunk by chunk, so that the memory does not grow with the # of records,
+// except for the values of the columns referred to by foreign keys, the values seen in unique keys
+// and the values deferred to UPDATE statements.
+// every random value comes from r, so that the same seed of r gives the same output.
+// the capacity of the unique values is checked for every table before any output, but a composite key which fails
+// to find an unused tuple in maxAttemptsForUniqueKey attempts is an error after the records written so far.
+func WriteDummyData(w io.Writer, r *rand.Rand, schema Schema, rn RecordNumber, opt Option) error {
+	if err := opt.Validate(); err != nil {
+		return err
+	}
+	rw := newRecordWriter(w, opt)
+	if err := writeRecords(rw, r, schema, rn, opt); err != nil {
+		return err
+	}
+	return rw.close()
+}
+
+func writeRecords(rw recordWriter, r *rand.Rand, schema Schema, rn RecordNumber, opt Option) error {
+	schema, err := schema.resolveForeignKeys()
+	if err != nil {
+		return err
+	}
+	stored, err := generateReferredValues(r, schema, rn, opt)
+	if err != nil {
+		return err
+	}
+	tables, deferred, err := orderTables(schema, opt)
+	if err != nil {
+		return err
+	}
+	uw, canUpdate := rw.(updateWriter)
+	if len(deferred) > 0 && !canUpdate {
+		return errors.Errorf("cannot break the cycles of foreign keys in %s format, which has no UPDATE statements", opt.Format)
+	}
+	omitted := omittedColumns(schema, opt)
+	defaultable := defaultableColumns(schema)
+	// all the streams are built before the first record is written,
+	// so that the errors of the domain sizes and the keys of any table leave no partial output
+	streams := make([]*tableStream, 0, len(tables))
+	for _, table := range tables {
+		ts, err := newTableStream(r, table, schema, rn.Of(table.Name), opt, stored, defaultable, omitted, deferred)
+		if err != nil {
+			return err
+		}
+		streams = append(streams, ts)
+	}
+	updated := []*tableStream{}
+	for _, ts := range streams {
+		table := ts.table
+		n := rn.Of(table.Name)
+		rw.beginTable(table, ts.columns)
+
+		// the records of a chunk share a buffer, which is reused for the next chunk after written
+		width := len(ts.columns)
+		buf := make([]Value, chunkSize*width)
+		records := make([]Record, 0, chunkSize)
+		for i := 0; i < n; i++ {
+			record := Record(buf[len(records)*width : (len(records)+1)*width])
+			if err := ts.generate(r, i, record); err != nil {
+				return err
+			}
+			records = append(records, record)
+			if len(records) == chunkSize || i == n-1 {
+				rw.writeRecords(records)
+				records = records[:0]
+			}
+		}
+		rw.endTable()
+		if len(ts.updates) > 0 {
+			updated = append(updated, ts)
+		}
+	}
+	for _, ts := range updated {
+		uw.writeUpdates(ts.table, ts.deferredColumns(), ts.keyColumns(), ts.updates)
+	}
+	return nil
+}
+
+// generateReferredValues generates in advance the values of the columns referred to by foreign keys,
+// which have to be kept for the child rows to refer to.
+// the values of the other columns are generated row by row, see tableStream.
+func generateReferredValues(r *rand.Rand, schema Schema, rn RecordNumber, opt Option) (map[ColumnFullName][]Value, error) {
+	referred := referredColumns(schema)
+	s := Schema{}
+	for _, table := range schema.Tables {
+		t := table
+		t.Columns = nil
+		for _, c := range table.Columns {
+			if referred[c.FullName] {
+				t.Columns = append(t.Columns, c)
+			}
+		}
+		if len(t.Columns) > 0 {
+			s.AddTable(t)
+		}
+	}
+	return GenerateValuesForColumns(r, GenerateColumnGraph(s), rn, opt)
+}
+
+// tableStream generates the records of a table one by one.
+type tableStream struct {
+	table   Table
+	columns []Column
+	streams []*columnStream
+	// foreignKeys is the composite foreign keys, and foreignKeyOf is the one which the column at the index follows
+	foreignKeys  []*foreignKeyStream
+	foreignKeyOf map[int]*foreignKeyStream
+	keys         []*keyStream
+	// deferred is the indexes of the columns whose values are inserted as NULL and set by UPDATE statements
+	deferred []int
+	// primaryKey is the indexes of the primary key columns, which are -1 for auto increment columns
+	primaryKey []int
+	// updates is the values of the deferred columns and the primary key of the rows to update
+	updates []Record
+}
+
+func newTableStream(r *rand.Rand, table Table, schema Schema, n int, opt Option, stored map[ColumnFullName][]Value, defaultable, omitted, deferred map[ColumnFullName]bool) (*tableStream, error) {
+	ts := &tableStream{table: table, columns: insertedColumns(table, omitted), foreignKeyOf: map[int]*foreignKeyStream{}}
+	index := map[ColumnName]int{}
+	unique := map[ColumnName]bool{}
+	for _, key := range table.Keys() {
+		if len(key) == 1 {
+			unique[key[0]] = true
+		}
+	}
+	for i, c := range ts.columns {
+		index[c.Name] = i
+		s, err := newColumnStream(r, c, schema, n, opt, stored, unique[c.Name], defaultable[c.FullName])
+		if err != nil {
+			return nil, err
+		}
+		ts.streams = append(ts.streams, s)
+		if deferred[c.FullName] {
+			ts.deferred = append(ts.deferred, i)
+		}
+	}
+	added := map[string]bool{}
+	for _, c := range ts.columns {
+		constraint, ok := c.compositeConstraint()
+		if !ok || added[constraint.key()] {
+			continue
+		}
+		added[constraint.key()] = true
+		fs, err := newForeignKeyStream(r, table, constraint, schema, n, opt, stored, ts.columns, index)
+		if err != nil {
+			return nil, err
+		}
+		ts.foreignKeys = append(ts.foreignKeys, fs)
+		for _, j := range fs.indexes {
+			if j >= 0 {
+				ts.foreignKeyOf[j] = fs
+			}
+		}
+	}
+	if len(ts.deferred) > 0 {
+		for _, cn := range table.PrimaryKey {
+			j, ok := index[cn]
+			if !ok {
+				if c, _ := table.Column(cn); c.Generated {
+					return nil, errors.Errorf("cannot set the deferred columns of %s by UPDATE statements, because its primary key has the generated column %s", table.Name, c.Name)
+				}
+				j = -1
+			}
+			ts.primaryKey = append(ts.primaryKey, j)
+		}
+	}
+
+	keys := table.Keys()
+	for i, key := range keys {
+		// a key of a single column is unique on generation, except for a column of a composite foreign key
+		if j, ok := index[key[0]]; len(key) < 2 && (!ok || ts.foreignKeyOf[j] == nil) {
+			continue
+		}
+		fixed := map[ColumnName]bool{}
+		for j, other := range keys {
+			if j != i {
+				for _, cn := range other {
+					fixed[cn] = true
+				}
+			}
+		}
+		ks, err := newKeyStream(table, key, n, ts.streams, index, fixed)
+		if err != nil {
+			return nil, err
+		}
+		if ks != nil {
+			ts.keys = append(ts.keys, ks)
+		}
+	}
+	return ts, nil
+}
+
+// generate fills the record with the values of the i-th row.
+func (ts *tableStream) generate(r *rand.Rand, i int, record Record) error {
+	for j, s := range ts.streams {
+		v, err := s.value(r, i)
+		if err != nil {
+			return err
+		}
+		record[j] = v
+	}
+	for _, fs := range ts.foreignKeys {
+		if err := fs.generate(r, i, record, ts.streams); err != nil {
+			return err
+		}
+	}
+	for _, ks := range ts.keys {
+		if err := ks.check(r, record, ts); err != nil {
+			return err
+		}
+	}
+	ts.deferValues(i, record)
+	return nil
+}
+
+// deferValues replaces the values of the deferred columns of the i-th row by NULL, keeping them for the UPDATE statement.
+func (ts *tableStream) deferValues(i int, record Record) {
+	if len(ts.deferred) == 0 {
+		return
+	}
+	update := make(Record, 0, len(ts.deferred)+len(ts.primaryKey))
+	hasValue := false
+	for _, j := range ts.deferred {
+		update = append(update, record[j])
+		hasValue = hasValue || !record[j].IsNull()
+		record[j] = NullValue
+	}
+	if !hasValue {
+		return
+	}
+	for k, j := range ts.primaryKey {
+		if j < 0 {
+			// the same as the values referred to, see referableValues
+			c, _ := ts.table.Column(ts.table.PrimaryKey[k])
+			update = append(update, c.autoIncrementValue(i))
+		} else {
+			update = append(update, record[j])
+		}
+	}
+	ts.updates = append(ts.updates, update)
+}
+
+// regenerate replaces the values at the indexes of the record by other values which are not NULL.
+// the columns of a composite foreign key are replaced together by the values of another parent row.
+func (ts *tableStream) regenerate(r *rand.Rand, record Record, indexes []int) error {
+	done := map[*foreignKeyStream]bool{}
+	for _, j := range indexes {
+		fs, ok := ts.foreignKeyOf[j]
+		if !ok {
+			record[j] = ts.streams[j].regenerate(r)
+			continue
+		}
+		if done[fs] {
+			continue
+		}
+		done[fs] = true
+		if err := fs.regenerate(r, record, indexes); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (ts *tableStream) deferredColumns() []Column {
+	columns := make([]Column, 0, len(ts.deferred))
+	for _, j := range ts.deferred {
+		columns = append(columns, ts.columns[j])
+	}
+	return columns
+}
+
+func (ts *tableStream) keyColumns() []Column {
+	columns := make([]Column, 0, len(ts.table.PrimaryKey))
+	for _, cn := range ts.table.PrimaryKey {
+		c, _ := ts.table.Column(cn)
+		columns = append(columns, c)
+	}
+	return columns
+}
+
+// columnStream generates the values of a column one by one.
+type columnStream struct {
+	column   Column
+	nullRate float64
+	// defaultRate is the rate of DEFAULT, which is 0 unless the column can be left to its DEFAULT clause
+	defaultRate float64
+	// specialRate is the rate of the special values, which is 0 unless the column is of FLOAT or DOUBLE
+	specialRate float64
+	// stored is the values generated in advance, for the column referred to by foreign keys
+	stored []Value
+	// parentValues is the values which the column can refer to by its foreign key
+	parentValues []Value
+	// hierarchical is true for the column referring to the same table, whose values are chosen out of the rows of the parent,
+	// see hierarchyValue
+	hierarchical bool
+	parent       Column
+	parentRows   []Value
+	n            int
+	depth        int
+	cardinality  Cardinality
+	unique       bool
+	// perm is the order in which the parent values are used, for a unique column with a foreign key
+	perm []int
+	// seen is the values already used, for a unique column without a foreign key
+	seen       map[Value]bool
+	domainSize uint64
+	// composite is true for the column of a composite foreign key, whose values are set by foreignKeyStream
+	composite bool
+}
+
+func newColumnStream(r *rand.Rand, c Column, schema Schema, n int, opt Option, stored map[ColumnFullName][]Value, unique, defaultable bool) (*columnStream, error) {
+	c, err := c.withOption(opt)
+	if err != nil {
+		return nil, err
+	}
+	s := &columnStream{
+		column:      c,
+		nullRate:    c.nullRate(opt),
+		cardinality: opt.CardinalityOf(c.FullName),
+		unique:      unique,
+	}
+	if defaultable {
+		s.defaultRate = opt.DefaultRate
+	}
+	if c.Type.Base == Float || c.Type.Base == Double {
+		s.specialRate = opt.SpecialFloatRate
+	}
+	if values, ok := stored[c.FullName]; ok {
+		s.stored = values
+		return s, nil
+	}
+
+	if constraint, ok := c.compositeConstraint(); ok {
+		s.composite = true
+		if parent, ok := schema.column(constraint.TableName, constraint.ColumnName); ok {
+			s.domainSize = uint64(len(distinctValues(c, referableValues(parent, stored[parent.FullName]))))
+		}
+		return s, nil
+	}
+
+	parent, hasParent := firstParent(c, schema)
+	switch {
+	case hasParent && isHierarchy(c, parent, unique):
+		s.hierarchical = true
+		s.parent = parent
+		s.parentRows = stored[parent.FullName]
+		s.n = n
+		s.depth = opt.hierarchyDepth()
+	case hasParent && unique:
+		// a value can satisfy only one of the foreign keys in general, so the first parent is used
+		s.parentValues = distinctValues(c, referableValues(parent, stored[parent.FullName]))
+		if m := nonNullCount(n, s.nullRate); len(s.parentValues) < m {
+			return nil, errors.Errorf("cannot generate %d unique values for %s, because the referred column has only %d distinct values", m, c.FullName, len(s.parentValues))
+		}
+		s.perm = r.Perm(len(s.parentValues))
+	case hasParent:
+		s.parentValues = referableValues(parent, stored[parent.FullName])
+		if s.nullRate < 1 && n > 0 && len(s.parentValues) == 0 {
+			return nil, errors.Errorf("cannot generate values for %s, because the referred column has no values", c.FullName)
+		}
+	case unique:
+		s.domainSize = c.domainSize()
+		if m := nonNullCount(n, s.nullRate); s.domainSize < uint64(m) {
+			return nil, errors.Errorf("cannot generate %d unique values for %s, because %s can have only %d distinct values", m, c.FullName, c.Type.Base, s.domainSize)
+		}
+		s.seen = map[Value]bool{}
+	}
+	return s, nil
+}
+
+// nonNullCount returns the # of the rows expected to be non-NULL.
+// the capacity of a unique column is checked with it before any output,
+// and the rows beyond the capacity by the randomness of NULL are set to NULL on generation.
+func nonNullCount(n int, nullRate float64) int {
+	return int(math.Floor(float64(n) * (1 - nullRate)))
+}
+
+// value returns the value of the i-th row.
+func (s *columnStream) value(r *rand.Rand, i int) (Value, error) {
+	if s.stored != nil {
+		return s.stored[i], nil
+	}
+	if s.composite {
+		return Value{}, nil
+	}
+	// the roots of the trees are NULL instead of the null rate
+	if s.hierarchical {
+		return hierarchyValue(r, s.column, s.parent, s.parentRows, i, s.n, s.depth), nil
+	}
+	if isNull(r, s.nullRate) {
+		return NullValue, nil
+	}
+	var v Value
+	switch {
+	case s.parentValues != nil && s.unique:
+		// only a nullable column can run out of the values, see newColumnStream
+		if len(s.perm) == 0 {
+			return NullValue, nil
+		}
+		v = s.parentValues[s.perm[0]]
+		s.perm = s.perm[1:]
+	case s.parentValues != nil:
+		if len(s.parentValues) == 0 {
+			return Value{}, errors.Errorf("cannot generate values for %s, because the referred column has no values", s.column.FullName)
+		}
+		v = s.parentValues[s.cardinality.ParentIndex(r, i, len(s.parentValues))]
+	case s.unique:
+		if uint64(len(s.seen)) >= s.domainSize {
+			return NullValue, nil
+		}
+		for {
+			v = s.column.GenerateRandomData(r)
+			if u := s.column.uniqueValue(v); !s.seen[u] {
+				s.seen[u] = true
+				break
+			}
+		}
+	case s.specialRate > 0 && r.Float64() < s.specialRate:
+		v = generateSpecialFloat(r, s.column.Type, s.column.Unsigned)
+	default:
+		v = s.column.GenerateRandomData(r)
+	}
+	if s.defaultRate > 0 && r.Float64() < s.defaultRate {
+		return DefaultValue, nil
+	}
+	return v, nil
+}
+
+// regenerate returns another value for the column, which is not NULL.
+func (s *columnStream) regenerate(r *rand.Rand) Value {
+	if s.hierarchical {
+		parentValues := referableValues(s.parent, s.parentRows)
+		return parentValues[r.Intn(len(parentValues))]
+	}
+	if s.parentValues != nil {
+		return s.parentValues[r.Intn(len(s.parentValues))]
+	}
+	return s.column.GenerateRandomData(r)
+}
+
+// keyStream keeps a composite key unique by regenerating the values of the rows whose key is duplicated.
+// keys of a single column are already unique on generation, see columnStream, except for the columns of composite foreign keys.
+// only the columns which no foreign key refers to and which belong to no other key are regenerated,
+// so that the values already referred to or checked are kept.
+type keyStream struct {
+	key Key
+	// indexes is the positions of the columns of the key in the record
+	indexes     []int
+	regenerable []int
+	seen        map[string]bool
+}
+
+// newKeyStream returns nil for a key which needs no check.
+func newKeyStream(table Table, key Key, n int, streams []*columnStream, index map[ColumnName]int, fixed map[ColumnName]bool) (*keyStream, error) {
+	ks := &keyStream{key: key, seen: map[string]bool{}}
+	size := uint64(1)
+	notNullRate := 1.0
+	storedOnly := true
+	for _, cn := range key {
+		j, ok := index[cn]
+		if !ok {
+			// an auto increment column is unique by itself
+			return nil, nil
+		}
+		s := streams[j]
+		ks.indexes = append(ks.indexes, j)
+		size = mulSaturated(size, s.keyDomainSize())
+		notNullRate *= 1 - s.nullRate
+		storedOnly = storedOnly && s.stored != nil
+		if s.stored == nil && !fixed[cn] {
+			ks.regenerable = append(ks.regenerable, j)
+		}
+	}
+	// unique keys allow any number of rows with NULL, see check for the rows beyond the capacity
+	if m := nonNullCount(n, 1-notNullRate); size < uint64(m) {
+		return nil, errors.Errorf("cannot generate %d unique values for the key (%s) of %s, because it can have only %d distinct values", m, joinKey(key), table.Name, size)
+	}
+	// the values generated in advance cannot be regenerated, so that their duplicates are found before any output
+	if storedOnly {
+		if err := ks.checkStored(table, n, streams); err != nil {
+			return nil, err
+		}
+		return nil, nil
+	}
+	return ks, nil
+}
+
+func (ks *keyStream) checkStored(table Table, n int, streams []*columnStream) error {
+	record := make(Record, len(streams))
+	columns := make([]Column, len(streams))
+	for _, j := range ks.indexes {
+		columns[j] = streams[j].column
+	}
+	for i := 0; i < n; i++ {
+		for _, j := range ks.indexes {
+			record[j] = streams[j].stored[i]
+		}
+		k, hasNull := uniqueKey(record, ks.indexes, columns)
+		if hasNull {
+			continue
+		}
+		if ks.seen[k] {
+			return errors.Errorf("cannot generate unique values for the key (%s) of %s", joinKey(ks.key), table.Name)
+		}
+		ks.seen[k] = true
+	}
+	return nil
+}
+
+// keyDomainSize returns the # of distinct values of the column as a part of a key.
+// a column with a foreign key can have only the values of its parent.
+func (s *columnStream) keyDomainSize() uint64 {
+	switch {
+	case s.stored != nil:
+		return uint64(len(distinctValues(s.column, referableValues(s.column, s.stored))))
+	case s.composite:
+		return s.domainSize
+	case s.hierarchical:
+		return uint64(len(distinctValues(s.column, referableValues(s.parent, s.parentRows))))
+	case s.parentValues != nil:
+		return uint64(len(distinctValues(s.column, s.parentValues)))
+	default:
+		return s.column.domainSize()
+	}
+}
+
+func (ks *keyStream) check(r *rand.Rand, record Record, ts *tableStream) error {
+	for attempt := 0; ; attempt++ {
+		k, hasNull := uniqueKey(record, ks.indexes, ts.columns)
+		// unique keys allow any number of rows with NULL
+		if hasNull {
+			return nil
+		}
+		if !ks.seen[k] {
+			ks.seen[k] = true
+			return nil
+		}
+		if len(ks.regenerable) == 0 || attempt >= maxAttemptsForUniqueKey {
+			// NULL keeps the rows beyond the capacity of a nullable key unique, see newKeyStream
+			for _, j := range ks.regenerable {
+				if ts.streams[j].nullRate > 0 {
+					record[j] = NullValue
+					return nil
+				}
+			}
+			return errors.Errorf("cannot generate unique values for the key (%s) of %s", joinKey(ks.key), ts.table.Name)
+		}
+		if err := ts.regenerate(r, record, ks.regenerable); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,923 @@
+package model
+
+import (
+	"bytes"
+	"math/rand"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// recordCollector keeps the written records and updates of each table
+type recordCollector struct {
+	columns map[TableName][]Column
+	records map[TableName][]Record
+	updates map[TableName][]Record
+	// order is the tables in the order written
+	order   []TableName
+	current TableName
+}
+
+func newRecordCollector() *recordCollector {
+	return &recordCollector{columns: map[TableName][]Column{}, records: map[TableName][]Record{}, updates: map[TableName][]Record{}}
+}
+
+func (c *recordCollector) beginTable(table Table, columns []Column) {
+	c.current = table.Name
+	c.order = append(c.order, table.Name)
+	c.columns[table.Name] = columns
+	c.records[table.Name] = []Record{}
+}
+
+func (c *recordCollector) writeRecords(records []Record) {
+	for _, record := range records {
+		// the records are reused after written
+		c.records[c.current] = append(c.records[c.current], append(Record{}, record...))
+	}
+}
+
+func (c *recordCollector) endTable() {}
+
+func (c *recordCollector) close() error { return nil }
+
+func (c *recordCollector) writeUpdates(table Table, columns []Column, keyColumns []Column, rows []Record) {
+	c.updates[table.Name] = append(c.updates[table.Name], rows...)
+}
+
+// column returns the values of the column in the written records
+func (c *recordCollector) column(fn ColumnFullName) []Value {
+	tn := fn.TableName()
+	for j, column := range c.columns[tn] {
+		if column.FullName == fn {
+			vs := []Value{}
+			for _, record := range c.records[tn] {
+				vs = append(vs, record[j])
+			}
+			return vs
+		}
+	}
+	return nil
+}
+
+func Test_writeRecords(t *testing.T) {
+	tinyint := ColumnType{Base: Tinyint, Param: 1}
+	type args struct {
+		schema Schema
+		rn     RecordNumber
+		opt    Option
+	}
+	tests := []struct {
+		name     string
+		args     args
+		assertFn func(*testing.T, *recordCollector)
+		wantErr  bool
+	}{
+		{
+			name: "generate the # of records for each table, beyond a chunk",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "table1", Columns: []Column{{Name: "test1", FullName: "table1.test1", Type: ColumnType{Base: Int}}}},
+						{Name: "table2", Columns: []Column{{Name: "test1", FullName: "table2.test1", Type: ColumnType{Base: Varchar, Param: 3}}}},
+					},
+				},
+				rn:  RecordNumber{Default: chunkSize*2 + 1, Tables: map[TableName]int{"table2": 1}},
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				if got := len(c.records["table1"]); got != chunkSize*2+1 {
+					t.Errorf("the # of records of table1 = %v", got)
+				}
+				if got := len(c.records["table2"]); got != 1 {
+					t.Errorf("the # of records of table2 = %v", got)
+				}
+				if got := len(distinctValues(Column{}, c.column("table1.test1"))); got < 2 {
+					t.Errorf("the chunks share the same values; %v distinct values", got)
+				}
+			},
+		},
+		{
+			name: "skip auto increment columns and omitted columns",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "table1", Columns: []Column{
+							{Name: "test1", FullName: "table1.test1", Type: ColumnType{Base: Int}, AutoIncrement: true},
+							{Name: "test2", FullName: "table1.test2", Type: ColumnType{Base: Int}},
+							{Name: "test3", FullName: "table1.test3", Type: ColumnType{Base: Int}, Default: "1", HasDefault: true},
+						}},
+					},
+				},
+				rn:  NewRecordNumber(2),
+				opt: Option{OmitDefaults: true},
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				got := []ColumnName{}
+				for _, column := range c.columns["table1"] {
+					got = append(got, column.Name)
+				}
+				diff := cmp.Diff(got, []ColumnName{"test2"})
+				if diff != "" {
+					t.Errorf("written columns; -got, +want\n%v", diff)
+				}
+			},
+		},
+		{
+			name: "refer to the values of the parents, which are 1, 2, ... for auto increment columns",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "parent", Columns: []Column{{Name: "id", FullName: "parent.id", Type: ColumnType{Base: Int}, AutoIncrement: true}}},
+						{Name: "child", Columns: []Column{{Name: "parent_id", FullName: "child.parent_id", Type: ColumnType{Base: Int}, Constraints: []Constraint{{TableName: "parent", ColumnName: "id"}}}}},
+					},
+				},
+				rn:  RecordNumber{Default: 3, Tables: map[TableName]int{"child": 30}},
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				for i, v := range c.column("child.parent_id") {
+					if !containsValue([]Value{NewIntValue(1), NewIntValue(2), NewIntValue(3)}, v) {
+						t.Errorf("child.parent_id refers to no parent; idx: %v, value: %v", i, v)
+					}
+				}
+			},
+		},
+		{
+			name: "count the values of auto increment columns from the AUTO_INCREMENT option",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "parent", Columns: []Column{
+							{Name: "id", FullName: "parent.id", Type: ColumnType{Base: Int}, AutoIncrement: true, AutoIncrementStart: 5, NotNull: true},
+							{Name: "code", FullName: "parent.code", Type: ColumnType{Base: Int}, NotNull: true},
+							{Name: "manager_id", FullName: "parent.manager_id", Type: ColumnType{Base: Int}, Constraints: []Constraint{{TableName: "parent", ColumnName: "id"}}},
+						}, PrimaryKey: Key{"id"}},
+						{Name: "child", Columns: []Column{
+							{Name: "parent_id", FullName: "child.parent_id", Type: ColumnType{Base: Int}, NotNull: true, Constraints: []Constraint{{TableName: "parent", ColumnName: "id"}}},
+							{Name: "pid", FullName: "child.pid", Type: ColumnType{Base: Int}, NotNull: true, Constraints: compositeFK("parent", []ColumnName{"pid", "pcode"}, []ColumnName{"id", "code"})[:1]},
+							{Name: "pcode", FullName: "child.pcode", Type: ColumnType{Base: Int}, NotNull: true, Constraints: compositeFK("parent", []ColumnName{"pid", "pcode"}, []ColumnName{"id", "code"})[1:]},
+						}},
+					},
+				},
+				rn:  RecordNumber{Default: 3, Tables: map[TableName]int{"child": 30}},
+				opt: Option{HierarchyDepth: 3},
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				ids := []Value{NewIntValue(5), NewIntValue(6), NewIntValue(7)}
+				for _, fn := range []ColumnFullName{"parent.manager_id", "child.parent_id", "child.pid"} {
+					for i, v := range c.column(fn) {
+						if !v.IsNull() && !containsValue(ids, v) {
+							t.Errorf("%s refers to no parent; idx: %v, value: %v", fn, i, v)
+						}
+					}
+				}
+			},
+		},
+		{
+			name: "update the rows by the values of auto increment columns from the AUTO_INCREMENT option",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "employee", Columns: []Column{
+							{Name: "id", FullName: "employee.id", Type: ColumnType{Base: Int}, AutoIncrement: true, AutoIncrementStart: 5, NotNull: true},
+							{Name: "successor_id", FullName: "employee.successor_id", Type: ColumnType{Base: Int}, Constraints: []Constraint{{TableName: "employee", ColumnName: "id"}}},
+						}, PrimaryKey: Key{"id"}, UniqueKeys: []Key{{"successor_id"}}},
+					},
+				},
+				rn:  NewRecordNumber(3),
+				opt: Option{TableOrder: DependencyOrder, BreakCycles: true},
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				keys := []Value{}
+				for _, u := range c.updates["employee"] {
+					keys = append(keys, u[1])
+				}
+				diff := cmp.Diff(keys, []Value{NewIntValue(5), NewIntValue(6), NewIntValue(7)})
+				if diff != "" {
+					t.Errorf("primary keys of the updates; -got, +want\n%v", diff)
+				}
+			},
+		},
+		{
+			name: "replace all values of columns with DEFAULT clauses when the rate is 1",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "user", Columns: []Column{
+							{Name: "status", FullName: "user.status", Type: ColumnType{Base: Varchar, Param: 3}, Default: "'active'", HasDefault: true},
+							{Name: "name", FullName: "user.name", Type: ColumnType{Base: Varchar, Param: 3}},
+						}},
+					},
+				},
+				rn:  NewRecordNumber(2),
+				opt: Option{DefaultRate: 1},
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				diff := cmp.Diff(c.column("user.status"), []Value{DefaultValue, DefaultValue})
+				if diff != "" {
+					t.Errorf("values of user.status; -got, +want\n%v", diff)
+				}
+				if containsValue(c.column("user.name"), DefaultValue) {
+					t.Errorf("user.name has DEFAULT without DEFAULT clause")
+				}
+			},
+		},
+		{
+			name: "generate distinct values for a unique column",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "t", Columns: []Column{{Name: "a", FullName: "t.a", Type: ColumnType{Base: Varchar, Param: 2}}}, PrimaryKey: Key{"a"}},
+					},
+				},
+				rn:  NewRecordNumber(1000),
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				if got := len(distinctValues(Column{}, c.column("t.a"))); got != 1000 {
+					t.Errorf("values of t.a are not distinct; %v distinct values", got)
+				}
+			},
+		},
+		{
+			name: "regenerate the rows whose composite keys are duplicated",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "t", Columns: []Column{
+							{Name: "a", FullName: "t.a", Type: tinyint},
+							{Name: "b", FullName: "t.b", Type: tinyint},
+						}, PrimaryKey: Key{"a", "b"}},
+					},
+				},
+				rn:  NewRecordNumber(4),
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				seen := map[[2]Value]bool{}
+				for _, record := range c.records["t"] {
+					k := [2]Value{record[0], record[1]}
+					if seen[k] {
+						t.Errorf("key (a, b) is duplicated; %v", k)
+					}
+					seen[k] = true
+				}
+			},
+		},
+		{
+			name: "keep the values referred to by foreign keys and regenerate the others",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "parent", Columns: []Column{
+							{Name: "id", FullName: "parent.id", Type: tinyint, NotNull: true},
+							{Name: "seq", FullName: "parent.seq", Type: ColumnType{Base: Int}},
+						}, UniqueKeys: []Key{{"id", "seq"}}},
+						{Name: "child", Columns: []Column{
+							{Name: "x", FullName: "child.x", Type: tinyint, Constraints: []Constraint{{TableName: "parent", ColumnName: "id"}}},
+						}},
+					},
+				},
+				rn:  NewRecordNumber(10),
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				if got := len(distinctValues(Column{}, c.column("parent.seq"))); got != 10 {
+					t.Errorf("values of parent.seq are not distinct; %v distinct values", got)
+				}
+			},
+		},
+		{
+			name: "allow duplicated keys with NULL",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "t", Columns: []Column{
+							{Name: "a", FullName: "t.a", Type: tinyint},
+							{Name: "b", FullName: "t.b", Type: tinyint, NotNull: true},
+						}, UniqueKeys: []Key{{"a", "b"}}},
+					},
+				},
+				rn:  NewRecordNumber(5),
+				opt: Option{NullRate: 1},
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				if got := len(c.records["t"]); got != 5 {
+					t.Errorf("the # of records of t = %v", got)
+				}
+			},
+		},
+		{
+			name: "return error when the columns of the key cannot have enough distinct values",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "t", Columns: []Column{
+							{Name: "a", FullName: "t.a", Type: tinyint},
+							{Name: "b", FullName: "t.b", Type: tinyint},
+						}, UniqueKeys: []Key{{"a", "b"}}},
+					},
+				},
+				rn:  NewRecordNumber(5),
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {},
+			wantErr:  true,
+		},
+		{
+			name: "return error when no column of the duplicated key can be regenerated",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "t", Columns: []Column{
+							{Name: "a", FullName: "t.a", Type: tinyint},
+							{Name: "b", FullName: "t.b", Type: ColumnType{Base: Int}},
+						}, PrimaryKey: Key{"a", "b"}, UniqueKeys: []Key{{"a"}, {"b"}}},
+					},
+				},
+				rn:  NewRecordNumber(3),
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {},
+			wantErr:  true,
+		},
+		{
+			name: "insert NULL and update the values afterwards for the deferred columns",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "employee", Columns: []Column{
+							{Name: "id", FullName: "employee.id", Type: ColumnType{Base: Int}, AutoIncrement: true, NotNull: true},
+							{Name: "successor_id", FullName: "employee.successor_id", Type: ColumnType{Base: Int}, Constraints: []Constraint{{TableName: "employee", ColumnName: "id"}}},
+						}, PrimaryKey: Key{"id"}, UniqueKeys: []Key{{"successor_id"}}},
+					},
+				},
+				rn:  NewRecordNumber(3),
+				opt: Option{TableOrder: DependencyOrder, BreakCycles: true},
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				diff := cmp.Diff(c.column("employee.successor_id"), []Value{NullValue, NullValue, NullValue})
+				if diff != "" {
+					t.Errorf("inserted values of employee.successor_id; -got, +want\n%v", diff)
+				}
+				updates := c.updates["employee"]
+				if len(updates) != 3 {
+					t.Fatalf("the # of updates = %v", len(updates))
+				}
+				for i, u := range updates {
+					diff := cmp.Diff(u[1], NewIntValue(int64(i+1)))
+					if diff != "" {
+						t.Errorf("primary key of the update; -got, +want\n%v", diff)
+					}
+				}
+			},
+		},
+		{
+			name: "make trees of the rows by a self reference, whose roots are NULL",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "employee", Columns: []Column{
+							{Name: "id", FullName: "employee.id", Type: ColumnType{Base: Int}, AutoIncrement: true, NotNull: true},
+							{Name: "manager_id", FullName: "employee.manager_id", Type: ColumnType{Base: Int}, Constraints: []Constraint{{TableName: "employee", ColumnName: "id"}}},
+						}, PrimaryKey: Key{"id"}},
+					},
+				},
+				rn:  NewRecordNumber(6),
+				opt: Option{HierarchyDepth: 3},
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				// the levels are rows 1-2, 3-4 and 5-6
+				want := [][]Value{
+					{NullValue}, {NullValue},
+					{NewIntValue(1), NewIntValue(2)}, {NewIntValue(1), NewIntValue(2)},
+					{NewIntValue(3), NewIntValue(4)}, {NewIntValue(3), NewIntValue(4)},
+				}
+				for i, v := range c.column("employee.manager_id") {
+					if !containsValue(want[i], v) {
+						t.Errorf("row %v refers to %v, want one of %v", i+1, v, want[i])
+					}
+				}
+			},
+		},
+		{
+			name: "generate the values of columns referring to each other",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "a", Columns: []Column{
+							{Name: "b_id", FullName: "a.b_id", Type: ColumnType{Base: Int}, Constraints: []Constraint{{TableName: "b", ColumnName: "id"}}},
+						}},
+						{Name: "b", Columns: []Column{
+							{Name: "id", FullName: "b.id", Type: ColumnType{Base: Int}, NotNull: true, Constraints: []Constraint{{TableName: "a", ColumnName: "b_id"}}},
+							{Name: "x", FullName: "b.x", Type: ColumnType{Base: Int}, Constraints: []Constraint{{TableName: "b", ColumnName: "id"}}},
+						}, PrimaryKey: Key{"id"}},
+					},
+				},
+				rn:  RecordNumber{Default: 5, Tables: map[TableName]int{"b": 3}},
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				if !isSubset(c.column("a.b_id"), c.column("b.id")) {
+					t.Errorf("a.b_id refers to values out of b.id; %v, %v", c.column("a.b_id"), c.column("b.id"))
+				}
+				if !isSubset(c.column("b.id"), c.column("a.b_id")) {
+					t.Errorf("b.id refers to values out of a.b_id; %v, %v", c.column("b.id"), c.column("a.b_id"))
+				}
+			},
+		},
+		{
+			name: "refer to the same parent row by all the columns of a composite foreign key",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "refund", Columns: []Column{
+							{Name: "shop_id", FullName: "refund.shop_id", Type: ColumnType{Base: Int}, Constraints: compositeFK("order_item", []ColumnName{"shop_id", "order_id"}, []ColumnName{"shop_id", "order_id"})[:1]},
+							{Name: "order_id", FullName: "refund.order_id", Type: ColumnType{Base: Int}, Constraints: compositeFK("order_item", []ColumnName{"shop_id", "order_id"}, []ColumnName{"shop_id", "order_id"})[1:]},
+						}},
+						{Name: "order_item", Columns: []Column{
+							{Name: "shop_id", FullName: "order_item.shop_id", Type: ColumnType{Base: Int}, NotNull: true, Constraints: compositeFK("orders", []ColumnName{"shop_id", "order_id"}, []ColumnName{"shop_id", "id"})[:1]},
+							{Name: "order_id", FullName: "order_item.order_id", Type: ColumnType{Base: Int}, Constraints: compositeFK("orders", []ColumnName{"shop_id", "order_id"}, []ColumnName{"shop_id", "id"})[1:]},
+						}},
+						{Name: "orders", Columns: []Column{
+							{Name: "shop_id", FullName: "orders.shop_id", Type: tinyint, NotNull: true},
+							{Name: "id", FullName: "orders.id", Type: ColumnType{Base: Int}, NotNull: true},
+						}, PrimaryKey: Key{"shop_id", "id"}},
+					},
+				},
+				rn:  RecordNumber{Default: 200, Tables: map[TableName]int{"orders": 20}},
+				opt: Option{NullRate: 0.2},
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				orders := tuples(c.column("orders.shop_id"), c.column("orders.id"))
+				items := tuples(c.column("order_item.shop_id"), c.column("order_item.order_id"))
+				refunds := tuples(c.column("refund.shop_id"), c.column("refund.order_id"))
+				for k := range items {
+					if !orders[k] {
+						t.Errorf("order_item refers to (%v) out of orders", k)
+					}
+				}
+				for k := range refunds {
+					if !items[k] {
+						t.Errorf("refund refers to (%v) out of order_item", k)
+					}
+				}
+				if len(items) < 2 || len(refunds) < 2 {
+					t.Errorf("too few tuples are referred to; %v, %v", items, refunds)
+				}
+			},
+		},
+		{
+			name: "use every parent row once for a composite foreign key making a unique key",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "parent", Columns: []Column{
+							{Name: "x", FullName: "parent.x", Type: ColumnType{Base: Int}, NotNull: true},
+							{Name: "y", FullName: "parent.y", Type: ColumnType{Base: Int}, NotNull: true},
+						}, PrimaryKey: Key{"x", "y"}},
+						{Name: "child", Columns: []Column{
+							{Name: "x", FullName: "child.x", Type: ColumnType{Base: Int}, NotNull: true, Constraints: compositeFK("parent", []ColumnName{"x", "y"}, []ColumnName{"x", "y"})[:1]},
+							{Name: "y", FullName: "child.y", Type: ColumnType{Base: Int}, NotNull: true, Constraints: compositeFK("parent", []ColumnName{"x", "y"}, []ColumnName{"x", "y"})[1:]},
+						}, UniqueKeys: []Key{{"y", "x"}}},
+					},
+				},
+				rn:  NewRecordNumber(100),
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				parents := tuples(c.column("parent.x"), c.column("parent.y"))
+				children := tuples(c.column("child.x"), c.column("child.y"))
+				diff := cmp.Diff(children, parents)
+				if diff != "" {
+					t.Errorf("child tuples; -got, +want\n%v", diff)
+				}
+			},
+		},
+		{
+			name: "return error when a unique composite foreign key cannot have enough parent rows",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "parent", Columns: []Column{
+							{Name: "x", FullName: "parent.x", Type: ColumnType{Base: Int}, NotNull: true},
+							{Name: "y", FullName: "parent.y", Type: ColumnType{Base: Int}, NotNull: true},
+						}},
+						{Name: "child", Columns: []Column{
+							{Name: "x", FullName: "child.x", Type: ColumnType{Base: Int}, NotNull: true, Constraints: compositeFK("parent", []ColumnName{"x", "y"}, []ColumnName{"x", "y"})[:1]},
+							{Name: "y", FullName: "child.y", Type: ColumnType{Base: Int}, NotNull: true, Constraints: compositeFK("parent", []ColumnName{"x", "y"}, []ColumnName{"x", "y"})[1:]},
+						}, PrimaryKey: Key{"x", "y"}},
+					},
+				},
+				rn:  RecordNumber{Default: 3, Tables: map[TableName]int{"child": 4}},
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {},
+			wantErr:  true,
+		},
+		{
+			name: "leave generated columns out of the records and of the keys",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "user", Columns: []Column{
+							{Name: "code", FullName: "user.code", Type: tinyint, NotNull: true},
+							{Name: "full", FullName: "user.full", Type: ColumnType{Base: Varchar, Param: 16}, Generated: true},
+						}, UniqueKeys: []Key{{"code", "full"}}},
+					},
+				},
+				rn:  NewRecordNumber(3),
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				names := []ColumnName{}
+				for _, column := range c.columns["user"] {
+					names = append(names, column.Name)
+				}
+				if diff := cmp.Diff(names, []ColumnName{"code"}); diff != "" {
+					t.Errorf("columns; -got, +want\n%v", diff)
+				}
+			},
+		},
+		{
+			name: "refer to the columns whose names are in another case, comparing them case-insensitively",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "child", Columns: []Column{
+							{Name: "p_id", FullName: "child.p_id", Type: ColumnType{Base: Int}, NotNull: true, Constraints: []Constraint{{TableName: "P", ColumnName: "ID"}}},
+							{Name: "x", FullName: "child.x", Type: ColumnType{Base: Int}, NotNull: true, Constraints: compositeFK("p", []ColumnName{"X", "Y"}, []ColumnName{"X", "Y"})[:1]},
+							{Name: "y", FullName: "child.y", Type: ColumnType{Base: Int}, NotNull: true, Constraints: compositeFK("p", []ColumnName{"X", "Y"}, []ColumnName{"X", "Y"})[1:]},
+						}},
+						{Name: "p", Columns: []Column{
+							{Name: "id", FullName: "p.id", Type: ColumnType{Base: Int}, AutoIncrement: true, NotNull: true},
+							{Name: "x", FullName: "p.x", Type: ColumnType{Base: Int}, NotNull: true},
+							{Name: "y", FullName: "p.y", Type: ColumnType{Base: Int}, NotNull: true},
+						}, PrimaryKey: Key{"id"}},
+					},
+				},
+				rn:  NewRecordNumber(10),
+				opt: Option{TableOrder: DependencyOrder},
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				if diff := cmp.Diff(c.order, []TableName{"p", "child"}); diff != "" {
+					t.Errorf("table order; -got, +want\n%v", diff)
+				}
+				for i, v := range c.column("child.p_id") {
+					if !containsValue(referableValues(Column{AutoIncrement: true}, make([]Value, 10)), v) {
+						t.Errorf("child.p_id refers to no parent; idx: %v, value: %v", i, v)
+					}
+				}
+				parents := tuples(c.column("p.x"), c.column("p.y"))
+				for k := range tuples(c.column("child.x"), c.column("child.y")) {
+					if !parents[k] {
+						t.Errorf("child refers to no parent row by %v", k)
+					}
+				}
+			},
+		},
+		{
+			name: "return error when a foreign key refers to an undefined table",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "child", Columns: []Column{{Name: "parent_id", FullName: "child.parent_id", Type: ColumnType{Base: Int}, Constraints: []Constraint{{TableName: "parent", ColumnName: "id"}}}}},
+					},
+				},
+				rn:  NewRecordNumber(3),
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {},
+			wantErr:  true,
+		},
+		{
+			name: "return error when a foreign key refers to an undefined column",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "parent", Columns: []Column{{Name: "id", FullName: "parent.id", Type: ColumnType{Base: Int}}}},
+						{Name: "child", Columns: []Column{{Name: "parent_id", FullName: "child.parent_id", Type: ColumnType{Base: Int}, Constraints: []Constraint{{TableName: "parent", ColumnName: "code"}}}}},
+					},
+				},
+				rn:  NewRecordNumber(3),
+				opt: Option{TableOrder: DependencyOrder},
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {},
+			wantErr:  true,
+		},
+		{
+			name: "return error when a foreign key refers to a generated column",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "parent", Columns: []Column{{Name: "code", FullName: "parent.code", Type: ColumnType{Base: Int}, Generated: true}}},
+						{Name: "child", Columns: []Column{{Name: "parent_code", FullName: "child.parent_code", Type: ColumnType{Base: Int}, Constraints: []Constraint{{TableName: "parent", ColumnName: "code"}}}}},
+					},
+				},
+				rn:  NewRecordNumber(3),
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {},
+			wantErr:  true,
+		},
+		{
+			name: "generate unique values case-insensitively for the default collation",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "t", Columns: []Column{{Name: "ci", FullName: "t.ci", Type: ColumnType{Base: Varchar, Param: 1}, NotNull: true}}, UniqueKeys: []Key{{"ci"}}},
+					},
+				},
+				rn:  NewRecordNumber(36),
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				values := map[string]bool{}
+				for _, v := range c.column("t.ci") {
+					values[strings.ToLower(string(v.Data))] = true
+				}
+				if len(values) != 36 {
+					t.Errorf("distinct values of t.ci in keys = %v, want 36", len(values))
+				}
+			},
+		},
+		{
+			name: "generate unique tuples case-insensitively for the default collation",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "t", Columns: []Column{
+							{Name: "x", FullName: "t.x", Type: ColumnType{Base: Char, Param: 1}, NotNull: true},
+							{Name: "y", FullName: "t.y", Type: tinyint, NotNull: true},
+							{Name: "z", FullName: "t.z", Type: ColumnType{Base: Int}, NotNull: true},
+						}, UniqueKeys: []Key{{"x", "y"}}},
+					},
+				},
+				// all the 36 * 2 tuples
+				rn:  NewRecordNumber(72),
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				pairs := map[string]bool{}
+				for i, x := range c.column("t.x") {
+					pairs[strings.ToLower(string(x.Data))+","+string(c.column("t.y")[i].Data)] = true
+				}
+				if len(pairs) != 72 {
+					t.Errorf("distinct tuples of (t.x, t.y) in keys = %v, want 72", len(pairs))
+				}
+			},
+		},
+		{
+			name: "return error when a short unique varchar of the default collation cannot have enough distinct values",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "t", Columns: []Column{{Name: "ci", FullName: "t.ci", Type: ColumnType{Base: Varchar, Param: 1}, NotNull: true}}, UniqueKeys: []Key{{"ci"}}},
+					},
+				},
+				rn:  NewRecordNumber(62),
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {},
+			wantErr:  true,
+		},
+		{
+			name: "generate unique values case-sensitively for a binary collation",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "t", Columns: []Column{{Name: "cs", FullName: "t.cs", Type: ColumnType{Base: Varchar, Param: 1}, NotNull: true, Collation: "utf8mb4_bin"}}, UniqueKeys: []Key{{"cs"}}},
+					},
+				},
+				rn:  NewRecordNumber(62),
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {
+				if got := len(distinctValues(Column{}, c.column("t.cs"))); got != 62 {
+					t.Errorf("distinct values of t.cs = %v, want 62", got)
+				}
+			},
+		},
+		{
+			name: "return error when a unique foreign key cannot have enough distinct values",
+			args: args{
+				schema: Schema{
+					Tables: []Table{
+						{Name: "parent", Columns: []Column{{Name: "id", FullName: "parent.id", Type: ColumnType{Base: Int}, AutoIncrement: true}}},
+						{Name: "child", Columns: []Column{{Name: "parent_id", FullName: "child.parent_id", Type: ColumnType{Base: Int}, Constraints: []Constraint{{TableName: "parent", ColumnName: "id"}}}}, UniqueKeys: []Key{{"parent_id"}}},
+					},
+				},
+				rn:  RecordNumber{Default: 3, Tables: map[TableName]int{"child": 4}},
+				opt: NewOption(),
+			},
+			assertFn: func(t *testing.T, c *recordCollector) {},
+			wantErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newRecordCollector()
+			err := writeRecords(c, rand.New(rand.NewSource(1)), tt.args.schema, tt.args.rn, tt.args.opt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("writeRecords() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			tt.assertFn(t, c)
+		})
+	}
+}
+
+// compositeFK returns the constraints of the columns of a composite foreign key
+func compositeFK(tn TableName, columns, referencedColumns []ColumnName) []Constraint {
+	return NewForeignKeyConstraints(tn, columns, referencedColumns)
+}
+
+// tuples returns the set of the tuples of the values in the same rows, except the tuples with NULL
+func tuples(columns ...[]Value) map[string]bool {
+	set := map[string]bool{}
+	for i := range columns[0] {
+		record := Record{}
+		indexes := []int{}
+		for k, values := range columns {
+			record = append(record, values[i])
+			indexes = append(indexes, k)
+		}
+		if k, hasNull := tupleKey(record, indexes); !hasNull {
+			set[k] = true
+		}
+	}
+	return set
+}
+
+func TestWriteDummyData(t *testing.T) {
+	schema := Schema{
+		Tables: []Table{
+			{Name: "user", Columns: []Column{
+				{Name: "id", FullName: "user.id", Type: ColumnType{Base: Int}, AutoIncrement: true},
+				{Name: "name", FullName: "user.name", Type: ColumnType{Base: Varchar, Param: 8}},
+			}},
+		},
+	}
+	var buf bytes.Buffer
+	if err := WriteDummyData(&buf, rand.New(rand.NewSource(1)), schema, NewRecordNumber(3), Option{Dialect: ANSI}); err != nil {
+		t.Fatalf("WriteDummyData() error = %v", err)
+	}
+	got := buf.String()
+	if !strings.HasPrefix(got, `INSERT INTO "user"("name") VALUES ('`) || !strings.HasSuffix(got, "');\n\n") || strings.Count(got, "),(") != 2 {
+		t.Errorf("WriteDummyData() = %q", got)
+	}
+}
+
+func TestWriteDummyData_defaultInCSV(t *testing.T) {
+	// CSV has no DEFAULT, which would be loaded as NULL
+	schema := Schema{
+		Tables: []Table{
+			{Name: "user", Columns: []Column{
+				{Name: "status", FullName: "user.status", Type: ColumnType{Base: Varchar, Param: 8}, NotNull: true, Default: "'x'", HasDefault: true},
+			}},
+		},
+	}
+	var buf bytes.Buffer
+	err := WriteDummyData(&buf, rand.New(rand.NewSource(1)), schema, NewRecordNumber(3), Option{Format: CSV, DefaultRate: 1})
+	if err == nil || buf.Len() > 0 {
+		t.Errorf("WriteDummyData() error = %v, output = %q", err, buf.String())
+	}
+}
+
+func TestWriteDummyData_noOutputOnError(t *testing.T) {
+	// the error of a later table is found before the records of the first table are written
+	wide := Table{Name: "a", Columns: []Column{
+		{Name: "id", FullName: "a.id", Type: ColumnType{Base: Int}},
+		// more than the buffer of the writer
+		{Name: "name", FullName: "a.name", Type: ColumnType{Base: Varchar, Param: 100}},
+	}}
+	tests := []struct {
+		name   string
+		tables []Table
+		opt    Option
+	}{
+		{
+			name: "unique column beyond its domain",
+			tables: []Table{
+				{Name: "b", Columns: []Column{
+					{Name: "code", FullName: "b.code", Type: ColumnType{Base: Tinyint}},
+				}, UniqueKeys: []Key{{"code"}}},
+			},
+			opt: Option{Dialect: MySQL},
+		},
+		{
+			name: "nullable unique column beyond its domain by the non-NULL rows",
+			tables: []Table{
+				{Name: "b", Columns: []Column{
+					{Name: "code", FullName: "b.code", Type: ColumnType{Base: Tinyint}},
+				}, UniqueKeys: []Key{{"code"}}},
+			},
+			opt: Option{Dialect: MySQL, NullRate: 0.1},
+		},
+		{
+			name: "duplicated key of the values generated in advance",
+			tables: []Table{
+				{Name: "p", Columns: []Column{
+					{Name: "id", FullName: "p.id", Type: ColumnType{Base: Int}, NotNull: true},
+				}, PrimaryKey: Key{"id"}},
+				{Name: "c", Columns: []Column{
+					{Name: "p_id", FullName: "c.p_id", Type: ColumnType{Base: Int}, NotNull: true, Constraints: []Constraint{{TableName: "p", ColumnName: "id"}}},
+					{Name: "code", FullName: "c.code", Type: ColumnType{Base: Tinyint}, NotNull: true},
+				}, UniqueKeys: []Key{{"p_id", "code"}}},
+				{Name: "d", Columns: []Column{
+					{Name: "c_p_id", FullName: "d.c_p_id", Type: ColumnType{Base: Int}, Constraints: []Constraint{{TableName: "c", ColumnName: "p_id"}}},
+					{Name: "c_code", FullName: "d.c_code", Type: ColumnType{Base: Tinyint}, Constraints: []Constraint{{TableName: "c", ColumnName: "code"}}},
+				}},
+			},
+			opt: Option{Dialect: MySQL},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			schema := Schema{Tables: append([]Table{wide}, tt.tables...)}
+			rn := RecordNumber{Default: 300, Tables: map[TableName]int{"p": 2}}
+			var buf bytes.Buffer
+			err := WriteDummyData(&buf, rand.New(rand.NewSource(1)), schema, rn, tt.opt)
+			if err == nil {
+				t.Fatalf("WriteDummyData() error = nil")
+			}
+			if buf.Len() > 0 {
+				t.Errorf("WriteDummyData() wrote %q on error", buf.String())
+			}
+		})
+	}
+}
+
+func Test_writeRecords_nullableUniqueBeyondCapacity(t *testing.T) {
+	// the rows beyond the 256 values of tinyint, and the 512 tuples of the key, by the randomness of NULL are set to NULL
+	schema := Schema{
+		Tables: []Table{
+			{Name: "u", Columns: []Column{
+				{Name: "code", FullName: "u.code", Type: ColumnType{Base: Tinyint}},
+			}, UniqueKeys: []Key{{"code"}}},
+			{Name: "k", Columns: []Column{
+				{Name: "a", FullName: "k.a", Type: ColumnType{Base: Tinyint}},
+				{Name: "b", FullName: "k.b", Type: ColumnType{Base: Tinyint, Param: 1}},
+			}, UniqueKeys: []Key{{"a", "b"}}},
+		},
+	}
+	opt := NewOption()
+	opt.NullRate = 0.15
+	rn := RecordNumber{Default: 300, Tables: map[TableName]int{"k": 700}}
+	for seed := int64(1); seed <= 8; seed++ {
+		c := newRecordCollector()
+		if err := writeRecords(c, rand.New(rand.NewSource(seed)), schema, rn, opt); err != nil {
+			t.Errorf("writeRecords() with seed %d error = %v", seed, err)
+			continue
+		}
+		seen := map[Value]bool{}
+		for _, v := range c.column("u.code") {
+			if v == NullValue {
+				continue
+			}
+			if seen[v] {
+				t.Errorf("writeRecords() with seed %d wrote %v twice", seed, v)
+			}
+			seen[v] = true
+		}
+		a, b := c.column("k.a"), c.column("k.b")
+		keys := map[[2]Value]bool{}
+		for i := range a {
+			if a[i] == NullValue || b[i] == NullValue {
+				continue
+			}
+			if k := [2]Value{a[i], b[i]}; keys[k] {
+				t.Errorf("writeRecords() with seed %d wrote (%v, %v) twice", seed, a[i], b[i])
+			} else {
+				keys[k] = true
+			}
+		}
+	}
+}
+
+func Test_writeRecords_smallCompositeKey(t *testing.T) {
+	// 300 rows need the tuples of the key unique, not the values of each column
+	schema := Schema{
+		Tables: []Table{
+			{Name: "p", Columns: []Column{
+				{Name: "a", FullName: "p.a", Type: ColumnType{Base: Tinyint}, NotNull: true},
+				{Name: "b", FullName: "p.b", Type: ColumnType{Base: Tinyint}, NotNull: true},
+			}, PrimaryKey: Key{"a", "b"}},
+			{Name: "c", Columns: []Column{
+				{Name: "pa", FullName: "c.pa", Type: ColumnType{Base: Tinyint}, NotNull: true, Constraints: compositeFK("p", []ColumnName{"pa", "pb"}, []ColumnName{"a", "b"})[:1]},
+				{Name: "pb", FullName: "c.pb", Type: ColumnType{Base: Tinyint}, NotNull: true, Constraints: compositeFK("p", []ColumnName{"pa", "pb"}, []ColumnName{"a", "b"})[1:]},
+			}},
+		},
+	}
+	for seed := int64(1); seed <= 8; seed++ {
+		c := newRecordCollector()
+		if err := writeRecords(c, rand.New(rand.NewSource(seed)), schema, NewRecordNumber(300), NewOption()); err != nil {
+			t.Errorf("writeRecords() with seed %d error = %v", seed, err)
+			continue
+		}
+		if got := len(tuples(c.column("p.a"), c.column("p.b"))); got != 300 {
+			t.Errorf("writeRecords() with seed %d wrote %d distinct keys of p, want 300", seed, got)
+		}
+	}
+}
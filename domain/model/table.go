@@ -2,9 +2,14 @@ package model
 
 type TableName string
 
+// Key is the columns of a primary key or a unique key, in the order of the key definition
+type Key []ColumnName
+
 type Table struct {
-	Name    TableName
-	Columns []Column
+	Name       TableName
+	Columns    []Column
+	PrimaryKey Key
+	UniqueKeys []Key
 }
 
 func NewTable(name TableName, columns []Column) Table {
@@ -18,21 +23,81 @@ func (t *Table) AddColumns(column Column) {
 	t.Columns = append(t.Columns, column)
 }
 
-func GenerateRecordsForTables(vfc map[ColumnFullName][]Value, schema Schema, n int) map[TableName][]Record {
-	rft := map[TableName][]Record{}
-	for _, table := range schema.Tables {
-		records := []Record{}
-		for i := 0; i < n; i++ {
-			var record Record
-			for _, column := range table.Columns {
-				//skip auto increment column
-				if !column.AutoIncrement {
-					record = append(record, vfc[column.FullName][i])
-				}
-			}
-			records = append(records, record)
+// SetAutoIncrementStart sets the value of the AUTO_INCREMENT table option to the auto increment column,
+// which is the value of the first row inserted.
+func (t *Table) SetAutoIncrementStart(start int64) {
+	for i := range t.Columns {
+		if t.Columns[i].AutoIncrement {
+			t.Columns[i].AutoIncrementStart = start
+		}
+	}
+}
+
+// SetDefaultCollation sets the collation of the table, e.g. COLLATE=utf8mb4_bin, to the string columns without their own collations.
+func (t *Table) SetDefaultCollation(collation string) {
+	for i := range t.Columns {
+		if isText(t.Columns[i].Type.Base) && t.Columns[i].Collation == "" {
+			t.Columns[i].Collation = collation
+		}
+	}
+}
+
+func (t *Table) SetPrimaryKey(key Key) {
+	t.PrimaryKey = key
+}
+
+// AddUniqueKey adds the key unless the same key is already added
+func (t *Table) AddUniqueKey(key Key) {
+	for _, k := range t.UniqueKeys {
+		if k.equals(key) {
+			return
+		}
+	}
+	t.UniqueKeys = append(t.UniqueKeys, key)
+}
+
+// Keys returns the primary key and the unique keys, all of which have to be unique in the table.
+func (t Table) Keys() []Key {
+	keys := []Key{}
+	if len(t.PrimaryKey) > 0 {
+		keys = append(keys, t.PrimaryKey)
+	}
+	for _, k := range t.UniqueKeys {
+		if !k.equals(t.PrimaryKey) {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func (t Table) Column(name ColumnName) (Column, bool) {
+	for _, c := range t.Columns {
+		if c.Name == name {
+			return c, true
+		}
+	}
+	return Column{}, false
+}
+
+func (k Key) equals(l Key) bool {
+	if len(k) != len(l) {
+		return false
+	}
+	for i := range k {
+		if k[i] != l[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// insertedColumns returns the columns given values in INSERT statements, which are not auto increment, generated nor omitted.
+func insertedColumns(table Table, omitted map[ColumnFullName]bool) []Column {
+	columns := []Column{}
+	for _, c := range table.Columns {
+		if !c.AutoIncrement && !c.Generated && !omitted[c.FullName] {
+			columns = append(columns, c)
 		}
-		rft[table.Name] = records
 	}
-	return rft
+	return columns
 }
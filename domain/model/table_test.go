@@ -6,66 +6,103 @@ import (
 	"github.com/google/go-cmp/cmp"
 )
 
-func TestGenerateRecordsForTables(t *testing.T) {
+func TestTable_Keys(t *testing.T) {
+	tests := []struct {
+		name  string
+		table Table
+		want  []Key
+	}{
+		{
+			name: "return the primary key and the unique keys except the same one as the primary key",
+			table: Table{
+				PrimaryKey: Key{"id"},
+				UniqueKeys: []Key{{"code"}, {"id"}, {"a", "b"}},
+			},
+			want: []Key{{"id"}, {"code"}, {"a", "b"}},
+		},
+		{
+			name:  "return empty slice for table without keys",
+			table: Table{},
+			want:  []Key{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.table.Keys()
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("Table.Keys(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestTable_AddUniqueKey(t *testing.T) {
+	table := Table{}
+	table.AddUniqueKey(Key{"a", "b"})
+	table.AddUniqueKey(Key{"b", "a"})
+	table.AddUniqueKey(Key{"a", "b"})
+	diff := cmp.Diff(table.UniqueKeys, []Key{{"a", "b"}, {"b", "a"}})
+	if diff != "" {
+		t.Errorf("Table.AddUniqueKey(); -got, +want\n%v", diff)
+	}
+}
+
+func Test_insertedColumns(t *testing.T) {
 	type args struct {
-		vfc    map[ColumnFullName][]Value
-		schema Schema
-		n      int
+		table   Table
+		omitted map[ColumnFullName]bool
 	}
 	tests := []struct {
 		name string
 		args args
-		want map[TableName][]Record
+		want []Column
 	}{
 		{
-			name: "generate records for tables from map of column full-names and values",
+			name: "return columns excluding auto increment column",
 			args: args{
-				vfc: map[ColumnFullName][]Value{
-					"table1.test1": []Value{"1", "2", "3"},
-					"table1.test2": []Value{"aaa", "bbb", "ccc"},
-					"table2.test1": []Value{"v1", "v2", "v3"},
-				},
-				schema: Schema{
-					Tables: []Table{
-						{Name: "table1", Columns: []Column{{FullName: "table1.test1"}, {FullName: "table1.test2"}}},
-						{Name: "table2", Columns: []Column{{FullName: "table2.test1"}}},
+				table: Table{
+					Columns: []Column{
+						{Name: "test1"},
+						{Name: "test2", AutoIncrement: true},
+						{Name: "test3"},
 					},
 				},
-				n: 3,
-			},
-			want: map[TableName][]Record{
-				"table1": []Record{{"1", "aaa"}, {"2", "bbb"}, {"3", "ccc"}},
-				"table2": []Record{{"v1"}, {"v2"}, {"v3"}},
 			},
+			want: []Column{{Name: "test1"}, {Name: "test3"}},
 		},
 		{
-			name: "skip auto increment columns",
+			name: "return columns excluding generated column",
 			args: args{
-				vfc: map[ColumnFullName][]Value{
-					"table1.test1": []Value{"1", "2", "3"},
-					"table1.test2": []Value{"aaa", "bbb", "ccc"},
-					"table2.test1": []Value{"v1", "v2", "v3"},
-				},
-				schema: Schema{
-					Tables: []Table{
-						{Name: "table1", Columns: []Column{{FullName: "table1.test1", AutoIncrement: true}, {FullName: "table1.test2"}}},
-						{Name: "table2", Columns: []Column{{FullName: "table2.test1"}}},
+				table: Table{
+					Columns: []Column{
+						{Name: "test1"},
+						{Name: "test2", Generated: true},
 					},
 				},
-				n: 3,
 			},
-			want: map[TableName][]Record{
-				"table1": []Record{{"aaa"}, {"bbb"}, {"ccc"}},
-				"table2": []Record{{"v1"}, {"v2"}, {"v3"}},
+			want: []Column{{Name: "test1"}},
+		},
+		{
+			name: "return columns excluding omitted column",
+			args: args{
+				table: Table{
+					Columns: []Column{
+						{Name: "test1", FullName: "t.test1"},
+						{Name: "test2", FullName: "t.test2", HasDefault: true},
+					},
+				},
+				omitted: map[ColumnFullName]bool{"t.test2": true},
 			},
+			want: []Column{{Name: "test1", FullName: "t.test1"}},
 		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := GenerateRecordsForTables(tt.args.vfc, tt.args.schema, tt.args.n)
+			got := insertedColumns(tt.args.table, tt.args.omitted)
 			diff := cmp.Diff(got, tt.want)
 			if diff != "" {
-				t.Errorf("GenerateRecordsForTables(); -got, +want\n%v", diff)
+				t.Errorf("insertedColumns(); -got, +want %v", diff)
 			}
 		})
 	}
@@ -0,0 +1,209 @@
+package model
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// ValueKind is the type of a value, which decides how the value is written in each output format
+type ValueKind string
+
+const (
+	NullKind     ValueKind = "null"
+	DefaultKind  ValueKind = "default"
+	IntKind      ValueKind = "int"
+	DecimalKind  ValueKind = "decimal"
+	FloatKind    ValueKind = "float"
+	StringKind   ValueKind = "string"
+	BytesKind    ValueKind = "bytes"
+	TimeKind     ValueKind = "time"
+	JSONKind     ValueKind = "json"
+	BitKind      ValueKind = "bit"
+	GeometryKind ValueKind = "geometry"
+)
+
+// Value is a value of a column in a record.
+// Data is the canonical text of the value, e.g. -12, 3.14, 2006-01-02 15:04:05 and {"a":1}, the raw bytes for BytesKind
+// the binary digits for BitKind, e.g. 0101, and EWKT for GeometryKind, e.g. SRID=4326;POINT(139.7 35.6).
+// it is empty for NULL and DEFAULT. Value is comparable, so that it can be a key of maps.
+type Value struct {
+	Kind ValueKind
+	Data ColumnData
+}
+
+var NullValue = Value{Kind: NullKind}
+
+// DefaultValue is the default of the column, which only SQL can express by the DEFAULT keyword
+var DefaultValue = Value{Kind: DefaultKind}
+
+func NewIntValue(i int64) Value {
+	return Value{Kind: IntKind, Data: ColumnData(strconv.FormatInt(i, 10))}
+}
+
+func NewUintValue(u uint64) Value {
+	return Value{Kind: IntKind, Data: ColumnData(strconv.FormatUint(u, 10))}
+}
+
+// NewDecimalValue makes a value of a fixed-point number from its text, e.g. -123.45
+func NewDecimalValue(s string) Value {
+	return Value{Kind: DecimalKind, Data: ColumnData(s)}
+}
+
+func NewFloatValue(f float64) Value {
+	return Value{Kind: FloatKind, Data: floatData(f, 64)}
+}
+
+// NewFloat32Value makes a value of a single precision number, written in the shortest digits for float32, e.g. 0.1 instead of 0.10000000149011612
+func NewFloat32Value(f float32) Value {
+	return Value{Kind: FloatKind, Data: floatData(float64(f), 32)}
+}
+
+// floatData writes the number in the shortest digits of the bit size.
+// negative zero is written as -0e0, because mysql reads -0 as the integer 0, which loses the sign
+func floatData(f float64, bitSize int) ColumnData {
+	if f == 0 && math.Signbit(f) {
+		return ColumnData("-0e0")
+	}
+	return ColumnData(strconv.FormatFloat(f, 'g', -1, bitSize))
+}
+
+func NewStringValue(s string) Value {
+	return Value{Kind: StringKind, Data: ColumnData(s)}
+}
+
+func NewBytesValue(b []byte) Value {
+	return Value{Kind: BytesKind, Data: ColumnData(b)}
+}
+
+// NewTimeValue makes a value of date and time from its text, e.g. 2006-01-02 15:04:05, 2006-01-02, 15:04:05
+func NewTimeValue(s string) Value {
+	return Value{Kind: TimeKind, Data: ColumnData(s)}
+}
+
+// NewBitValue makes a value of a bit field from its binary digits, e.g. 0101 for bit(4)
+func NewBitValue(bits string) Value {
+	return Value{Kind: BitKind, Data: ColumnData(bits)}
+}
+
+// bitNumber returns the bit field as a decimal number, e.g. 5 for 0101
+func (v Value) bitNumber() string {
+	u, _ := strconv.ParseUint(string(v.Data), 2, 64)
+	return strconv.FormatUint(u, 10)
+}
+
+// NewGeometryValue makes a value of a geometry from its WKT, e.g. POINT(139.7 35.6), with the SRID unless it is 0
+func NewGeometryValue(srid int, wkt string) Value {
+	if srid == 0 {
+		return Value{Kind: GeometryKind, Data: ColumnData(wkt)}
+	}
+	return Value{Kind: GeometryKind, Data: ColumnData("SRID=" + strconv.Itoa(srid) + ";" + wkt)}
+}
+
+// geometrySQL renders the geometry as ST_GeomFromText with its SRID.
+// WKT is always in the order of longitude and latitude, which MySQL needs to be told for geographic SRIDs such as 4326.
+func (v Value) geometrySQL(d Dialect) string {
+	srid, wkt, hasSRID := strings.Cut(string(v.Data), ";")
+	if !hasSRID {
+		return "ST_GeomFromText(" + d.quoteString(srid) + ")"
+	}
+	args := d.quoteString(wkt) + ", " + strings.TrimPrefix(srid, "SRID=")
+	if d == MySQL {
+		args += ", 'axis-order=long-lat'"
+	}
+	return "ST_GeomFromText(" + args + ")"
+}
+
+// NewJSONValue makes a value of a JSON document, which must be valid JSON
+func NewJSONValue(s string) Value {
+	return Value{Kind: JSONKind, Data: ColumnData(s)}
+}
+
+func (v Value) IsNull() bool {
+	return v.Kind == NullKind
+}
+
+func (v Value) IsDefault() bool {
+	return v.Kind == DefaultKind
+}
+
+func (v Value) isNumber() bool {
+	return v.Kind == IntKind || v.Kind == DecimalKind || v.Kind == FloatKind
+}
+
+// SQL renders the value as a SQL literal of the dialect.
+// numbers are written as they are, binary data as a hex literal, bit fields as a bit literal, e.g. b'0101',
+// geometries by ST_GeomFromText and the others as an escaped string literal.
+func (v Value) SQL(d Dialect) string {
+	switch {
+	case v.IsNull():
+		return "NULL"
+	case v.IsDefault():
+		return "DEFAULT"
+	case v.isNumber():
+		return string(v.Data)
+	case v.Kind == BytesKind:
+		return "X'" + hex.EncodeToString([]byte(v.Data)) + "'"
+	case v.Kind == BitKind:
+		return "b'" + string(v.Data) + "'"
+	case v.Kind == GeometryKind:
+		return v.geometrySQL(d)
+	default:
+		return d.quoteString(string(v.Data))
+	}
+}
+
+// csvNull is the NULL in CSV, which MySQL LOAD DATA reads as NULL
+const csvNull = `\N`
+
+// CSVField renders the value as a field of CSV (RFC 4180).
+// NULL is written as \N without quotes, binary data in hex and bit fields as decimal numbers.
+// backslashes in the text are doubled as MySQL LOAD DATA reads them by default, so that the text \N is not read as NULL.
+func (v Value) CSVField() string {
+	var s string
+	switch {
+	case v.IsNull(), v.IsDefault():
+		return csvNull
+	case v.Kind == BytesKind:
+		s = hex.EncodeToString([]byte(v.Data))
+	case v.Kind == BitKind:
+		s = v.bitNumber()
+	default:
+		s = string(v.Data)
+	}
+	s = strings.ReplaceAll(s, `\`, `\\`)
+	if s == "" || strings.ContainsAny(s, ",\"\r\n") || strings.TrimSpace(s) != s {
+		return `"` + strings.ReplaceAll(s, `"`, `""`) + `"`
+	}
+	return s
+}
+
+// JSON renders the value as a JSON value.
+// numbers and JSON documents are embedded as they are, binary data is written in base64 and bit fields as numbers.
+func (v Value) JSON() string {
+	switch {
+	case v.IsNull(), v.IsDefault():
+		return "null"
+	case v.isNumber(), v.Kind == JSONKind:
+		return string(v.Data)
+	case v.Kind == BytesKind:
+		return jsonString(base64.StdEncoding.EncodeToString([]byte(v.Data)))
+	case v.Kind == BitKind:
+		return v.bitNumber()
+	default:
+		return jsonString(string(v.Data))
+	}
+}
+
+func jsonString(s string) string {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	// encoding a string never fails
+	_ = enc.Encode(s)
+	return strings.TrimSuffix(buf.String(), "\n")
+}
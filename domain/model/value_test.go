@@ -0,0 +1,231 @@
+package model
+
+import (
+	"math"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestValue_SQL(t *testing.T) {
+	type args struct {
+		d Dialect
+	}
+	tests := []struct {
+		name  string
+		value Value
+		args  args
+		want  string
+	}{
+		{
+			name:  "escape quotes, backslashes and control characters for mysql",
+			value: NewStringValue("a'b\\c\x00d\ne\rf\x1ag"),
+			args:  args{d: MySQL},
+			want:  `'a\'b\\c\0d\ne\rf\Zg'`,
+		},
+		{
+			name:  "escape only quotes for ansi",
+			value: NewStringValue("a'b\\c"),
+			args:  args{d: ANSI},
+			want:  `'a''b\c'`,
+		},
+		{
+			name:  "write numbers without quotes",
+			value: NewIntValue(-12),
+			args:  args{d: MySQL},
+			want:  "-12",
+		},
+		{
+			name:  "write decimals without quotes",
+			value: NewDecimalValue("-12.50"),
+			args:  args{d: MySQL},
+			want:  "-12.50",
+		},
+		{
+			name:  "write floats without quotes",
+			value: NewFloatValue(1.5e-7),
+			args:  args{d: MySQL},
+			want:  "1.5e-07",
+		},
+		{
+			name:  "write negative zero as a float literal, which keeps the sign",
+			value: NewFloat32Value(float32(math.Copysign(0, -1))),
+			args:  args{d: MySQL},
+			want:  "-0e0",
+		},
+		{
+			name:  "write binary data as a hex literal",
+			value: NewBytesValue([]byte("\x00'\xff")),
+			args:  args{d: MySQL},
+			want:  "X'0027ff'",
+		},
+		{
+			name:  "write geometries by ST_GeomFromText with the SRID in long-lat order for mysql",
+			value: NewGeometryValue(4326, "POINT(139.7 35.6)"),
+			args:  args{d: MySQL},
+			want:  "ST_GeomFromText('POINT(139.7 35.6)', 4326, 'axis-order=long-lat')",
+		},
+		{
+			name:  "write geometries by ST_GeomFromText with the SRID for ansi",
+			value: NewGeometryValue(4326, "POINT(139.7 35.6)"),
+			args:  args{d: ANSI},
+			want:  "ST_GeomFromText('POINT(139.7 35.6)', 4326)",
+		},
+		{
+			name:  "write geometries by ST_GeomFromText without SRID",
+			value: NewGeometryValue(0, "POINT(1 2)"),
+			args:  args{d: MySQL},
+			want:  "ST_GeomFromText('POINT(1 2)')",
+		},
+		{
+			name:  "write bit fields as a bit literal",
+			value: NewBitValue("0101"),
+			args:  args{d: ANSI},
+			want:  "b'0101'",
+		},
+		{
+			name:  "write time and JSON as string literals",
+			value: NewJSONValue(`{"a":"it's"}`),
+			args:  args{d: ANSI},
+			want:  `'{"a":"it''s"}'`,
+		},
+		{
+			name:  "write NULL as a keyword, not as a string",
+			value: NullValue,
+			args:  args{d: MySQL},
+			want:  "NULL",
+		},
+		{
+			name:  "write the text NULL as a string",
+			value: NewStringValue("NULL"),
+			args:  args{d: MySQL},
+			want:  "'NULL'",
+		},
+		{
+			name:  "write DEFAULT as a keyword",
+			value: DefaultValue,
+			args:  args{d: MySQL},
+			want:  "DEFAULT",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.value.SQL(tt.args.d)
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("Value.SQL(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestValue_CSVField(t *testing.T) {
+	tests := []struct {
+		name  string
+		value Value
+		want  string
+	}{
+		{
+			name:  "write plain text as it is",
+			value: NewTimeValue("2006-01-02 15:04:05"),
+			want:  "2006-01-02 15:04:05",
+		},
+		{
+			name:  "quote text with commas, quotes or line breaks",
+			value: NewJSONValue(`{"a":1,"b":2}`),
+			want:  `"{""a"":1,""b"":2}"`,
+		},
+		{
+			name:  "write NULL as \\N",
+			value: NullValue,
+			want:  `\N`,
+		},
+		{
+			name:  "escape the backslash of the text \\N to tell it from NULL",
+			value: NewStringValue(`\N`),
+			want:  `\\N`,
+		},
+		{
+			name:  "escape backslashes in text",
+			value: NewStringValue(`a\b,c\\`),
+			want:  `"a\\b,c\\\\"`,
+		},
+		{
+			name:  "quote empty text",
+			value: NewStringValue(""),
+			want:  `""`,
+		},
+		{
+			name:  "write binary data in hex",
+			value: NewBytesValue([]byte{0, 255}),
+			want:  "00ff",
+		},
+		{
+			name:  "write geometries in EWKT",
+			value: NewGeometryValue(4326, "LINESTRING(1 2,3 4)"),
+			want:  `"SRID=4326;LINESTRING(1 2,3 4)"`,
+		},
+		{
+			name:  "write bit fields as decimal numbers",
+			value: NewBitValue("0101"),
+			want:  "5",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.value.CSVField()
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("Value.CSVField(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
+
+func TestValue_JSON(t *testing.T) {
+	tests := []struct {
+		name  string
+		value Value
+		want  string
+	}{
+		{
+			name:  "write numbers as JSON numbers",
+			value: NewUintValue(18446744073709551615),
+			want:  "18446744073709551615",
+		},
+		{
+			name:  "write text as a JSON string",
+			value: NewStringValue("a\"<b>\n"),
+			want:  `"a\"<b>\n"`,
+		},
+		{
+			name:  "embed JSON documents as they are",
+			value: NewJSONValue(`{"a":[1,2]}`),
+			want:  `{"a":[1,2]}`,
+		},
+		{
+			name:  "write binary data in base64",
+			value: NewBytesValue([]byte{0, 255}),
+			want:  `"AP8="`,
+		},
+		{
+			name:  "write bit fields as JSON numbers",
+			value: NewBitValue("1111111111111111111111111111111111111111111111111111111111111111"),
+			want:  "18446744073709551615",
+		},
+		{
+			name:  "write NULL as null",
+			value: NullValue,
+			want:  "null",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.value.JSON()
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("Value.JSON(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,244 @@
+package model
+
+import (
+	"bufio"
+	"io"
+)
+
+// recordWriter writes the records of the tables in a format, table by table and chunk by chunk.
+type recordWriter interface {
+	beginTable(table Table, columns []Column)
+	writeRecords(records []Record)
+	endTable()
+	// close writes the end of the output and returns the first error in writing
+	close() error
+}
+
+// updateWriter writes the UPDATE statements which set the deferred values after all the records are inserted, see orderTables.
+type updateWriter interface {
+	// writeUpdates writes an UPDATE statement for each row,
+	// whose values are those of the columns followed by those of the primary key columns
+	writeUpdates(table Table, columns []Column, keyColumns []Column, rows []Record)
+}
+
+func newRecordWriter(w io.Writer, opt Option) recordWriter {
+	bw := bufio.NewWriter(w)
+	switch opt.Format {
+	case CSV:
+		return &csvWriter{w: bw}
+	case JSON:
+		return newJSONWriter(bw)
+	default:
+		return newSQLWriter(bw, opt)
+	}
+}
+
+// sqlWriter writes INSERT statements for each table, the statements are separated by an empty line.
+// the records of a table are split into statements by batchSize rows and by maxBytes bytes, unless they are 0.
+type sqlWriter struct {
+	w         *bufio.Writer
+	dialect   Dialect
+	batchSize int
+	maxBytes  int
+	// disableFKChecks is true when the tables are inserted regardless of foreign keys
+	disableFKChecks bool
+	// insert is the head of the INSERT statements for the current table
+	insert string
+	// rows and bytes are the # of rows and the size of the current statement
+	rows  int
+	bytes int
+	// row is the buffer for the values of a row
+	row []byte
+}
+
+func newSQLWriter(w *bufio.Writer, opt Option) *sqlWriter {
+	s := &sqlWriter{
+		w:         w,
+		dialect:   opt.dialect(),
+		batchSize: opt.BatchSize,
+		maxBytes:  opt.MaxStatementBytes,
+		// only MySQL can disable foreign key checks, and DependencyOrder needs no disabling
+		disableFKChecks: opt.dialect() == MySQL && opt.tableOrder() == FileOrder,
+	}
+	if s.disableFKChecks {
+		w.WriteString("SET foreign_key_checks = 0;\n\n")
+	}
+	return s
+}
+
+func (s *sqlWriter) beginTable(table Table, columns []Column) {
+	s.insert = "INSERT INTO " + s.dialect.QuoteIdentifier(string(table.Name)) + "(" + joinColumnNames(columns, s.dialect.QuoteIdentifier, ", ") + ") VALUES "
+	s.rows = 0
+}
+
+func (s *sqlWriter) writeRecords(records []Record) {
+	for _, record := range records {
+		s.row = append(s.row[:0], '(')
+		for i, v := range record {
+			if i > 0 {
+				s.row = append(s.row, ',')
+			}
+			s.row = append(s.row, v.SQL(s.dialect)...)
+		}
+		s.row = append(s.row, ')')
+
+		// a row longer than maxBytes is written in a statement by itself
+		if s.rows > 0 && (s.batchSize > 0 && s.rows >= s.batchSize || s.maxBytes > 0 && s.bytes+len(",;")+len(s.row) > s.maxBytes) {
+			s.endStatement()
+		}
+		if s.rows == 0 {
+			s.w.WriteString(s.insert)
+			s.bytes = len(s.insert)
+		} else {
+			s.w.WriteByte(',')
+			s.bytes++
+		}
+		s.w.Write(s.row)
+		s.bytes += len(s.row)
+		s.rows++
+	}
+}
+
+func (s *sqlWriter) endTable() {
+	s.endStatement()
+}
+
+func (s *sqlWriter) endStatement() {
+	if s.rows > 0 {
+		s.w.WriteString(";\n\n")
+	}
+	s.rows = 0
+}
+
+func (s *sqlWriter) writeUpdates(table Table, columns []Column, keyColumns []Column, rows []Record) {
+	for _, row := range rows {
+		s.w.WriteString("UPDATE " + s.dialect.QuoteIdentifier(string(table.Name)) + " SET ")
+		set := 0
+		for i, c := range columns {
+			// NULL is already inserted
+			if row[i].IsNull() {
+				continue
+			}
+			if set > 0 {
+				s.w.WriteString(", ")
+			}
+			s.w.WriteString(s.dialect.QuoteIdentifier(string(c.Name)) + " = " + row[i].SQL(s.dialect))
+			set++
+		}
+		s.w.WriteString(" WHERE ")
+		for i, c := range keyColumns {
+			if i > 0 {
+				s.w.WriteString(" AND ")
+			}
+			s.w.WriteString(s.dialect.QuoteIdentifier(string(c.Name)) + " = " + row[len(columns)+i].SQL(s.dialect))
+		}
+		s.w.WriteString(";\n\n")
+	}
+}
+
+func (s *sqlWriter) close() error {
+	if s.disableFKChecks {
+		s.w.WriteString("SET foreign_key_checks = 1;\n\n")
+	}
+	return s.w.Flush()
+}
+
+// csvWriter writes a CSV for each table, whose first line is the names of the columns.
+// the tables are separated by an empty line.
+type csvWriter struct {
+	w      *bufio.Writer
+	tables int
+}
+
+func (c *csvWriter) beginTable(table Table, columns []Column) {
+	if c.tables > 0 {
+		c.w.WriteByte('\n')
+	}
+	c.tables++
+	c.w.WriteString(joinColumnNames(columns, func(name string) string { return NewStringValue(name).CSVField() }, ","))
+	c.w.WriteByte('\n')
+}
+
+func (c *csvWriter) writeRecords(records []Record) {
+	for _, record := range records {
+		for i, v := range record {
+			if i > 0 {
+				c.w.WriteByte(',')
+			}
+			c.w.WriteString(v.CSVField())
+		}
+		c.w.WriteByte('\n')
+	}
+}
+
+func (c *csvWriter) endTable() {}
+
+func (c *csvWriter) close() error {
+	return c.w.Flush()
+}
+
+// jsonWriter writes an object from table names to the arrays of records, each of which is an object from column names to values.
+type jsonWriter struct {
+	w      *bufio.Writer
+	tables int
+	// names is the JSON strings of the names of the columns in the current table
+	names []string
+	rows  int
+}
+
+func newJSONWriter(w *bufio.Writer) *jsonWriter {
+	w.WriteByte('{')
+	return &jsonWriter{w: w}
+}
+
+func (j *jsonWriter) beginTable(table Table, columns []Column) {
+	if j.tables > 0 {
+		j.w.WriteByte(',')
+	}
+	j.tables++
+	j.names = make([]string, 0, len(columns))
+	for _, c := range columns {
+		j.names = append(j.names, jsonString(string(c.Name)))
+	}
+	j.rows = 0
+	j.w.WriteString(jsonString(string(table.Name)) + ":[")
+}
+
+func (j *jsonWriter) writeRecords(records []Record) {
+	for _, record := range records {
+		if j.rows > 0 {
+			j.w.WriteByte(',')
+		}
+		j.w.WriteByte('{')
+		for i, v := range record {
+			if i > 0 {
+				j.w.WriteByte(',')
+			}
+			j.w.WriteString(j.names[i])
+			j.w.WriteByte(':')
+			j.w.WriteString(v.JSON())
+		}
+		j.w.WriteByte('}')
+		j.rows++
+	}
+}
+
+func (j *jsonWriter) endTable() {
+	j.w.WriteByte(']')
+}
+
+func (j *jsonWriter) close() error {
+	j.w.WriteString("}\n")
+	return j.w.Flush()
+}
+
+func joinColumnNames(columns []Column, quote func(string) string, sep string) string {
+	re := ""
+	for i, c := range columns {
+		if i > 0 {
+			re += sep
+		}
+		re += quote(string(c.Name))
+	}
+	return re
+}
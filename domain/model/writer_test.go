@@ -0,0 +1,130 @@
+package model
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRecordWriter(t *testing.T) {
+	user := Table{
+		Name: "user",
+		Columns: []Column{
+			{Name: "id", FullName: "user.id", Type: ColumnType{Base: Int}},
+			{Name: "name", FullName: "user.name", Type: ColumnType{Base: Varchar}},
+			{Name: "memo", FullName: "user.memo", Type: ColumnType{Base: Json}},
+		},
+	}
+	empty := Table{
+		Name: "empty",
+		Columns: []Column{
+			{Name: "id", FullName: "empty.id", Type: ColumnType{Base: Int}},
+		},
+	}
+	// the records of user are written in two chunks
+	chunks := [][]Record{
+		{{NewIntValue(1), NewStringValue("a,b"), NullValue}},
+		{{NewIntValue(2), NewStringValue("it's"), NewJSONValue(`{"k":1}`)}},
+	}
+	type args struct {
+		opt Option
+	}
+	tests := []struct {
+		name string
+		args args
+		want string
+	}{
+		{
+			name: "write an INSERT statement for each table with records",
+			args: args{opt: Option{Format: SQL, Dialect: MySQL}},
+			want: "SET foreign_key_checks = 0;\n\n" +
+				"INSERT INTO `user`(`id`, `name`, `memo`) VALUES (1,'a,b',NULL),(2,'it\\'s','{\"k\":1}');\n\n" +
+				"SET foreign_key_checks = 1;\n\n",
+		},
+		{
+			name: "write INSERT statements without foreign key checks in ANSI SQL",
+			args: args{opt: Option{Format: SQL, Dialect: ANSI}},
+			want: "INSERT INTO \"user\"(\"id\", \"name\", \"memo\") VALUES (1,'a,b',NULL),(2,'it''s','{\"k\":1}');\n\n",
+		},
+		{
+			name: "write INSERT statements without foreign key checks in dependency order",
+			args: args{opt: Option{Format: SQL, Dialect: MySQL, TableOrder: DependencyOrder}},
+			want: "INSERT INTO `user`(`id`, `name`, `memo`) VALUES (1,'a,b',NULL),(2,'it\\'s','{\"k\":1}');\n\n",
+		},
+		{
+			name: "split INSERT statements by the # of rows",
+			args: args{opt: Option{Format: SQL, Dialect: ANSI, BatchSize: 1}},
+			want: "INSERT INTO \"user\"(\"id\", \"name\", \"memo\") VALUES (1,'a,b',NULL);\n\n" +
+				"INSERT INTO \"user\"(\"id\", \"name\", \"memo\") VALUES (2,'it''s','{\"k\":1}');\n\n",
+		},
+		{
+			name: "split INSERT statements by the size, writing a longer row by itself",
+			args: args{opt: Option{Format: SQL, Dialect: ANSI, MaxStatementBytes: 60}},
+			want: "INSERT INTO \"user\"(\"id\", \"name\", \"memo\") VALUES (1,'a,b',NULL);\n\n" +
+				"INSERT INTO \"user\"(\"id\", \"name\", \"memo\") VALUES (2,'it''s','{\"k\":1}');\n\n",
+		},
+		{
+			name: "keep rows in a statement within the size",
+			args: args{opt: Option{Format: SQL, Dialect: ANSI, BatchSize: 2, MaxStatementBytes: 100}},
+			want: "INSERT INTO \"user\"(\"id\", \"name\", \"memo\") VALUES (1,'a,b',NULL),(2,'it''s','{\"k\":1}');\n\n",
+		},
+		{
+			name: "write a CSV with a header for each table",
+			args: args{opt: Option{Format: CSV}},
+			want: "id,name,memo\n1,\"a,b\",\\N\n2,it's,\"{\"\"k\"\":1}\"\n\nid\n",
+		},
+		{
+			name: "write a JSON object from table names to records",
+			args: args{opt: Option{Format: JSON}},
+			want: `{"user":[{"id":1,"name":"a,b","memo":null},{"id":2,"name":"it's","memo":{"k":1}}],"empty":[]}` + "\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			rw := newRecordWriter(&buf, tt.args.opt)
+			rw.beginTable(user, user.Columns)
+			for _, records := range chunks {
+				rw.writeRecords(records)
+			}
+			rw.endTable()
+			rw.beginTable(empty, empty.Columns)
+			rw.endTable()
+			if err := rw.close(); err != nil {
+				t.Fatalf("close() error = %v", err)
+			}
+			diff := cmp.Diff(buf.String(), tt.want)
+			if diff != "" {
+				t.Errorf("recordWriter; -got, +want\n%v", diff)
+			}
+		})
+	}
+}
+
+func Test_sqlWriter_writeUpdates(t *testing.T) {
+	table := Table{
+		Name: "employee",
+		Columns: []Column{
+			{Name: "id", FullName: "employee.id"},
+			{Name: "manager_id", FullName: "employee.manager_id"},
+			{Name: "mentor_id", FullName: "employee.mentor_id"},
+		},
+		PrimaryKey: Key{"id"},
+	}
+	var buf bytes.Buffer
+	rw := newRecordWriter(&buf, Option{Dialect: ANSI})
+	rw.(updateWriter).writeUpdates(table, table.Columns[1:], table.Columns[:1], []Record{
+		{NewIntValue(1), NewIntValue(3), NewIntValue(2)},
+		{NewIntValue(2), NullValue, NewIntValue(3)},
+	})
+	if err := rw.close(); err != nil {
+		t.Fatalf("close() error = %v", err)
+	}
+	want := "UPDATE \"employee\" SET \"manager_id\" = 1, \"mentor_id\" = 3 WHERE \"id\" = 2;\n\n" +
+		"UPDATE \"employee\" SET \"manager_id\" = 2 WHERE \"id\" = 3;\n\n"
+	diff := cmp.Diff(buf.String(), want)
+	if diff != "" {
+		t.Errorf("writeUpdates(); -got, +want\n%v", diff)
+	}
+}
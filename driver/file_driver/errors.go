@@ -0,0 +1,35 @@
+package file_driver
+
+import "fmt"
+
+// ParseError is the error for the schema file which cannot be read as MySQL DDL.
+// it tells where the problem is and which token causes it.
+type ParseError struct {
+	FilePath string
+	// Line and Column are 1-origin
+	Line   int
+	Column int
+	// Token is the offending token as written in the file, e.g. `name`, 'abc', (
+	Token   string
+	Message string
+	// Err is the underlying error if exists, e.g. model.ErrUnregisteredType
+	Err error
+}
+
+func (e *ParseError) Error() string {
+	return fmt.Sprintf("%s:%d:%d: %s (near %s)", e.FilePath, e.Line, e.Column, e.Message, e.Token)
+}
+
+func (e *ParseError) Unwrap() error {
+	return e.Err
+}
+
+func newParseError(tok token, err error, format string, a ...interface{}) *ParseError {
+	return &ParseError{
+		Line:    tok.line,
+		Column:  tok.column,
+		Token:   tok.String(),
+		Message: fmt.Sprintf(format, a...),
+		Err:     err,
+	}
+}
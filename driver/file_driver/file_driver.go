@@ -1,22 +1,14 @@
 package file_driver
 
 import (
-	"bufio"
-	"fmt"
 	"os"
-	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/canalun/sqloth/domain/model"
+	"github.com/pkg/errors"
 )
 
-var regexForTable = regexp.MustCompile("(?m)^ *CREATE TABLE .*")
-var regexForColumn = regexp.MustCompile("(?m)^ *`.*` *[^ ]+.*,")
-var regexForAutoIncrement = regexp.MustCompile("AUTO_INCREMENT")
-var regexForUnsigned = regexp.MustCompile("(UNSIGNED)|(Unsigned)|(unsigned)")
-var regexForColumnConstraint = regexp.MustCompile("(?m)^ *CONSTRAINT .*")
-
 type FileDriver struct {
 	FilePath string
 }
@@ -27,124 +19,163 @@ func NewFileDriver(filePath string) FileDriver {
 	}
 }
 
-// TODO: fix to handle multi-index-key
-func (fd FileDriver) GetSchema() model.Schema {
-	f, err := os.Open(fd.FilePath)
+func (fd FileDriver) GetSchema() (model.Schema, error) {
+	b, err := os.ReadFile(fd.FilePath)
 	if err != nil {
-		fmt.Println("error, cannot open the file")
-		return model.Schema{}
+		return model.Schema{}, errors.Wrap(err, "cannot open the schema file")
 	}
-	defer f.Close()
-
-	schema := model.Schema{}
-
-	scanner := bufio.NewScanner(f)
-	for scanner.Scan() {
-		line := scanner.Text()
-
-		tableLines := regexForTable.FindStringSubmatch(line)
-		if len(tableLines) > 0 {
-			// assuming tableLines are like ["CREATE TABLE `name` ..."]
-			_tableName := trimSqlQuery(strings.Fields(tableLines[0])[2])
-			tableName := model.TableName(_tableName)
-
-			table := model.NewTable(tableName, []model.Column{})
-			schema.AddTable(table)
-		}
-
-		columnLines := regexForColumn.FindStringSubmatch(line)
-		if len(columnLines) > 0 {
-			// assuming columnLines are like ["  `name` type ..."]
-			_columnName := trimSqlQuery(strings.Fields(columnLines[0])[0])
-			columnName := model.ColumnName(_columnName)
 
-			_columnFullName := string(schema.LastTable().Name) + "." + string(columnName)
-			columnFullName := model.ColumnFullName(_columnFullName)
-
-			columnType, err := strToColumnType(strings.Fields(columnLines[0])[1])
-			if err != nil {
-				fmt.Println("unexpected data type")
-				return model.Schema{}
-			}
-
-			column := model.NewColumn(columnFullName, columnType)
-			if len(regexForAutoIncrement.FindStringSubmatch(columnLines[0])) > 0 {
-				column.SetAutoIncrement()
-			}
-			if len(regexForUnsigned.FindStringSubmatch(columnLines[0])) > 0 {
-				column.SetUnsigned(true)
-			} else {
-				column.SetUnsigned(false)
-			}
-			schema.LastTable().AddColumns(column)
+	schema, err := parseSchema(string(b))
+	if err != nil {
+		var pe *ParseError
+		if errors.As(err, &pe) {
+			pe.FilePath = fd.FilePath
 		}
+		return model.Schema{}, err
+	}
+	if len(schema.Tables) == 0 {
+		return model.Schema{}, errors.Errorf("%s: no CREATE TABLE statement is found", fd.FilePath)
+	}
 
-		columnKeyLines := regexForColumnConstraint.FindStringSubmatch(line)
-		if len(columnKeyLines) > 0 {
-			// assuming columnLines are like [" CONSTRAINT `XX` FOREIGN KEY (`column_name`) REFERENCES `table_name` (`column_name`) ..."]
-			_boundedColumnName := trimSqlQuery(strings.Fields(columnKeyLines[0])[4])
-			boundedColumnName := model.ColumnName(_boundedColumnName)
-
-			_referencedTableName := trimSqlQuery(strings.Fields(columnKeyLines[0])[6])
-			referencedTableName := model.TableName(_referencedTableName)
+	return schema, nil
+}
 
-			_referencedColumnName := trimSqlQuery(strings.Fields(columnKeyLines[0])[7])
-			referencedColumnName := model.ColumnName(_referencedColumnName)
+// the TEXT and BLOB families in the order of the max sizes
+var largeObjectFamilies = map[model.ColumnTypeBase][]model.ColumnTypeBase{
+	model.Text: {model.Tinytext, model.Text, model.Mediumtext, model.Longtext},
+	model.Blob: {model.Tinyblob, model.Blob, model.Mediumblob, model.Longblob},
+}
 
-			constraint := model.NewConstraint(referencedTableName, referencedColumnName)
-			for i, c := range schema.LastTable().Columns {
-				if c.Name == boundedColumnName {
-					schema.LastTable().Columns[i].Constraints = append(schema.LastTable().Columns[i].Constraints, constraint)
-				}
-			}
+// smallestLargeObject returns the smallest type of the family of TEXT or BLOB which can hold the length.
+func smallestLargeObject(base model.ColumnTypeBase, length int64) model.ColumnTypeBase {
+	family := largeObjectFamilies[base]
+	for _, t := range family {
+		if length <= model.MaxLengthOf(t) {
+			return t
 		}
 	}
-
-	if err := scanner.Err(); err != nil {
-		fmt.Println("error, something happens when/while reading the schema")
-		return model.Schema{}
-	}
-
-	return schema
+	return family[len(family)-1]
 }
 
-func strToColumnType(str string) (ct model.ColumnType, err error) {
-	f := func(r rune) bool {
-		return r == '(' || r == ')' || r == ','
+// membersToColumnType makes ENUM or SET out of the members, e.g. ["draft", "published"] of enum('draft','published').
+func membersToColumnType(typeName string, base model.ColumnTypeBase, members []string) (model.ColumnType, error) {
+	max := model.MaxEnumMembers
+	if base == model.Set {
+		max = model.MaxSetMembers
+	}
+	if len(members) == 0 || len(members) > max {
+		return model.ColumnType{}, errors.Errorf("the # of the members of %s must be from 1 to %d", typeName, max)
 	}
-	l := strings.FieldsFunc(str, f)
+	seen := map[string]bool{}
+	for _, m := range members {
+		// MySQL compares the members case-insensitively by default
+		key := strings.ToLower(m)
+		if seen[key] {
+			return model.ColumnType{}, errors.Errorf("%s has the duplicate member %q", typeName, m)
+		}
+		seen[key] = true
+		if base == model.Set && strings.Contains(m, ",") {
+			return model.ColumnType{}, errors.Errorf("the member %q of %s must not contain commas", m, typeName)
+		}
+	}
+	return model.ColumnType{Base: base, Members: members}, nil
+}
 
-	base, err := model.StrToColumnTypeBase(strings.ToLower(l[0]))
+// strToColumnType converts a data type and its parameters such as "varchar" and ["255"] into model.ColumnType.
+func strToColumnType(typeName string, params []string) (ct model.ColumnType, err error) {
+	base, err := model.StrToColumnTypeBase(strings.ToLower(typeName))
 	if err != nil {
 		return model.ColumnType{}, err
 	}
+	if base == model.Enum || base == model.Set {
+		return membersToColumnType(typeName, base, params)
+	}
 
-	var param int
-	if len(l) > 1 {
-		param, err = strconv.Atoi(l[1])
+	var param, scale int
+	if len(params) > 0 {
+		param, err = strconv.Atoi(params[0])
 		if err != nil {
 			return model.ColumnType{}, err
 		}
 	}
-	if base == model.Text {
-		param = 100
+	if len(params) > 1 {
+		scale, err = strconv.Atoi(params[1])
+		if err != nil {
+			return model.ColumnType{}, err
+		}
+	}
+	if base == model.Decimal {
+		// DECIMAL is DECIMAL(10,0), and DECIMAL(M) is DECIMAL(M,0)
+		if len(params) == 0 {
+			param = model.DefaultDecimalPrecision
+		}
+		if param < 1 || param > model.MaxDecimalPrecision {
+			return model.ColumnType{}, errors.Errorf("the precision of %s must be from 1 to %d", typeName, model.MaxDecimalPrecision)
+		}
+		if scale > model.MaxDecimalScale || scale > param {
+			return model.ColumnType{}, errors.Errorf("the scale of %s must be from 0 to %d and not greater than the precision", typeName, model.MaxDecimalScale)
+		}
+	}
+	if base == model.Float && len(params) == 1 {
+		// FLOAT(p) only tells the precision in bits, and it is DOUBLE for p over 24
+		if param < 0 || param > model.MaxDoublePrecision {
+			return model.ColumnType{}, errors.Errorf("the precision of %s must be from 0 to %d", typeName, model.MaxDoublePrecision)
+		}
+		if param > model.MaxFloatPrecision {
+			base = model.Double
+		}
+		param = 0
+	}
+	if (base == model.Float || base == model.Double) && len(params) > 1 {
+		if param < 1 || param > model.MaxFloatDigits {
+			return model.ColumnType{}, errors.Errorf("the # of digits of %s must be from 1 to %d", typeName, model.MaxFloatDigits)
+		}
+		if scale > model.MaxDecimalScale || scale > param {
+			return model.ColumnType{}, errors.Errorf("the scale of %s must be from 0 to %d and not greater than the # of digits", typeName, model.MaxDecimalScale)
+		}
 	}
-	// TODO: handle varbinary appropriately
-	if base == model.Varbinary {
-		param = 100
+	if base == model.Datetime || base == model.Timestamp || base == model.Time {
+		if param < 0 || param > model.MaxFsp {
+			return model.ColumnType{}, errors.Errorf("the fractional seconds precision of %s must be from 0 to %d", typeName, model.MaxFsp)
+		}
+	}
+	if base == model.Year {
+		// YEAR(4) is the same as YEAR
+		param = 0
+	}
+	if t := strings.ToLower(typeName); t == "bool" || t == "boolean" {
+		// BOOL is TINYINT(1)
+		param = 1
+	}
+	if base == model.Bit {
+		// BIT is BIT(1)
+		if len(params) == 0 {
+			param = 1
+		}
+		if param < 1 || param > model.MaxBitWidth {
+			return model.ColumnType{}, errors.Errorf("the width of %s must be from 1 to %d", typeName, model.MaxBitWidth)
+		}
 	}
-	// TODO: handle mediumblob appropriately
-	if base == model.Mediumblob {
-		param = 100
+	if base == model.Char || base == model.Binary {
+		// CHAR is CHAR(1)
+		if len(params) == 0 {
+			param = 1
+		}
+	}
+	if (base == model.Text || base == model.Blob) && len(params) > 0 {
+		// TEXT(M) and BLOB(M) are the smallest types of the families which can hold M characters or bytes
+		base = smallestLargeObject(base, int64(param))
+	}
+	if model.IsLargeObject(base) {
+		param = int(model.MaxLengthOf(base))
+	}
+	if max := model.MaxLengthOf(base); max > 0 && (param < 0 || int64(param) > max) {
+		return model.ColumnType{}, errors.Errorf("the length of %s must be from 0 to %d", typeName, max)
 	}
 
 	return model.ColumnType{
 		Base:  base,
 		Param: model.ColumnTypeParam(param),
+		Scale: model.ColumnTypeParam(scale),
 	}, nil
 }
-
-func trimSqlQuery(str string) string {
-	trimmingTarget := "()`"
-	return strings.Trim(str, trimmingTarget)
-}
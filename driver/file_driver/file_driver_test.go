@@ -1,10 +1,14 @@
 package file_driver
 
 import (
+	"errors"
+	"os"
+	"path/filepath"
 	"testing"
 
 	"github.com/canalun/sqloth/domain/model"
 	"github.com/google/go-cmp/cmp"
+	"github.com/google/go-cmp/cmp/cmpopts"
 )
 
 func TestGetSchema(t *testing.T) {
@@ -35,6 +39,7 @@ func TestGetSchema(t *testing.T) {
 								},
 								AutoIncrement: true,
 								Unsigned:      true,
+								NotNull:       true,
 							},
 							{
 								Name:     "created_at",
@@ -42,6 +47,9 @@ func TestGetSchema(t *testing.T) {
 								Type: model.ColumnType{
 									Base: model.Timestamp,
 								},
+								NotNull:    true,
+								Default:    "CURRENT_TIMESTAMP",
+								HasDefault: true,
 							},
 							{
 								Name:     "name",
@@ -50,6 +58,8 @@ func TestGetSchema(t *testing.T) {
 									Base:  model.Varchar,
 									Param: model.ColumnTypeParam(255),
 								},
+								Default:    "NULL",
+								HasDefault: true,
 							},
 							{
 								Name:     "material",
@@ -59,6 +69,8 @@ func TestGetSchema(t *testing.T) {
 								},
 							},
 						},
+						PrimaryKey: model.Key{"id"},
+						UniqueKeys: []model.Key{{"name"}},
 					},
 					{
 						Name: "product",
@@ -71,6 +83,7 @@ func TestGetSchema(t *testing.T) {
 									Param: model.ColumnTypeParam(14),
 								},
 								AutoIncrement: true,
+								NotNull:       true,
 							},
 							{
 								Name:     "name",
@@ -79,6 +92,8 @@ func TestGetSchema(t *testing.T) {
 									Base:  model.Varchar,
 									Param: model.ColumnTypeParam(255),
 								},
+								Default:    "NULL",
+								HasDefault: true,
 							},
 							{
 								Name:     "owner",
@@ -87,6 +102,8 @@ func TestGetSchema(t *testing.T) {
 									Base:  model.Varchar,
 									Param: model.ColumnTypeParam(255),
 								},
+								Default:    "NULL",
+								HasDefault: true,
 								Constraints: []model.Constraint{
 									{
 										TableName:  model.TableName("customer"),
@@ -99,8 +116,10 @@ func TestGetSchema(t *testing.T) {
 								FullName: "product.description",
 								Type: model.ColumnType{
 									Base:  model.Text,
-									Param: model.ColumnTypeParam(100),
+									Param: model.ColumnTypeParam(65535),
 								},
+								Default:    "NULL",
+								HasDefault: true,
 							},
 							{
 								Name:     "stock",
@@ -116,8 +135,12 @@ func TestGetSchema(t *testing.T) {
 								Type: model.ColumnType{
 									Base: model.Datetime,
 								},
+								Default:    "NULL",
+								HasDefault: true,
 							},
 						},
+						PrimaryKey: model.Key{"id"},
+						UniqueKeys: []model.Key{{"name"}},
 					},
 				},
 			},
@@ -128,7 +151,11 @@ func TestGetSchema(t *testing.T) {
 			fd := FileDriver{
 				FilePath: tt.fields.FilePath,
 			}
-			got := fd.GetSchema()
+			got, err := fd.GetSchema()
+			if err != nil {
+				t.Errorf("GetSchema() error = %v", err)
+				return
+			}
 			diff := cmp.Diff(got, tt.want)
 			if diff != "" {
 				t.Error("-:got, +:want", diff)
@@ -137,9 +164,98 @@ func TestGetSchema(t *testing.T) {
 	}
 }
 
+func TestGetSchema_Error(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	type fields struct {
+		FilePath string
+	}
+	tests := []struct {
+		name         string
+		fields       fields
+		wantParseErr *ParseError
+		wantWrapped  error
+	}{
+		{
+			name:   "return error when the file does not exist",
+			fields: fields{FilePath: filepath.Join(dir, "not_exist.sql")},
+		},
+		{
+			name:   "return error when the file has no CREATE TABLE statement",
+			fields: fields{FilePath: write("empty.sql", "-- nothing here\n")},
+		},
+		{
+			name:   "return ParseError with position and token for unregistered data type",
+			fields: fields{FilePath: write("unregistered.sql", "CREATE TABLE `a` (\n  `id` int,\n  `b` unknowntype\n);")},
+			wantParseErr: &ParseError{
+				Line:    3,
+				Column:  7,
+				Token:   "unknowntype",
+				Message: "unregistered type",
+			},
+			wantWrapped: model.ErrUnregisteredType,
+		},
+		{
+			name:   "return ParseError with position and token for syntax error",
+			fields: fields{FilePath: write("syntax.sql", "CREATE TABLE `a` (\n  `id` int;")},
+			wantParseErr: &ParseError{
+				Line:    2,
+				Column:  11,
+				Token:   ";",
+				Message: "expected ) but got ;",
+			},
+		},
+		{
+			name:   "return ParseError with position of the foreign key referring to undefined table",
+			fields: fields{FilePath: write("undefined_fk.sql", "CREATE TABLE `a` (\n  `b_id` int,\n  FOREIGN KEY (`b_id`) REFERENCES `b` (`id`)\n);")},
+			wantParseErr: &ParseError{
+				Line:    3,
+				Column:  3,
+				Token:   "FOREIGN",
+				Message: "table b referred to by the foreign key of table a is not defined",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fd := FileDriver{
+				FilePath: tt.fields.FilePath,
+			}
+			got, err := fd.GetSchema()
+			if err == nil {
+				t.Errorf("GetSchema() error = nil, schema = %v", got)
+				return
+			}
+			if tt.wantParseErr != nil {
+				var pe *ParseError
+				if !errors.As(err, &pe) {
+					t.Errorf("GetSchema() error = %v, want ParseError", err)
+					return
+				}
+				tt.wantParseErr.FilePath = tt.fields.FilePath
+				diff := cmp.Diff(pe, tt.wantParseErr, cmpopts.IgnoreFields(ParseError{}, "Err"))
+				if diff != "" {
+					t.Errorf("GetSchema(); -got, +want\n%v", diff)
+				}
+			}
+			if tt.wantWrapped != nil && !errors.Is(err, tt.wantWrapped) {
+				t.Errorf("GetSchema() error = %v, want wrapping %v", err, tt.wantWrapped)
+			}
+		})
+	}
+}
+
 func Test_strToColumnType(t *testing.T) {
 	type args struct {
-		str string
+		typeName string
+		params   []string
 	}
 	tests := []struct {
 		name    string
@@ -149,7 +265,7 @@ func Test_strToColumnType(t *testing.T) {
 	}{
 		{
 			name: "set base of type without param(e.g. text, json)",
-			args: args{str: "JSON"},
+			args: args{typeName: "JSON"},
 			wantCt: model.ColumnType{
 				Base: model.Json,
 			},
@@ -157,7 +273,7 @@ func Test_strToColumnType(t *testing.T) {
 		},
 		{
 			name: "separate and set base and param of type with param(e.g. varchar, int)",
-			args: args{str: "VARCHAR(255)"},
+			args: args{typeName: "VARCHAR", params: []string{"255"}},
 			wantCt: model.ColumnType{
 				Base:  model.Varchar,
 				Param: model.ColumnTypeParam(255),
@@ -165,18 +281,157 @@ func Test_strToColumnType(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name: "set base and additional param for type TEXT",
-			args: args{str: "TEXT"},
+			name: "set base and the max size for type TEXT",
+			args: args{typeName: "TEXT"},
 			wantCt: model.ColumnType{
 				Base:  model.Text,
-				Param: model.ColumnTypeParam(100),
+				Param: model.ColumnTypeParam(65535),
 			},
 			wantErr: false,
 		},
+		{
+			name:   "set precision and scale of DECIMAL",
+			args:   args{typeName: "decimal", params: []string{"10", "2"}},
+			wantCt: model.ColumnType{Base: model.Decimal, Param: 10, Scale: 2},
+		},
+		{
+			name:   "set the default precision and scale of NUMERIC",
+			args:   args{typeName: "NUMERIC"},
+			wantCt: model.ColumnType{Base: model.Decimal, Param: model.DefaultDecimalPrecision},
+		},
+		{
+			name:    "return error for the scale of DECIMAL greater than the precision",
+			args:    args{typeName: "decimal", params: []string{"3", "4"}},
+			wantCt:  model.ColumnType{},
+			wantErr: true,
+		},
+		{
+			name:    "return error for the precision of DECIMAL out of range",
+			args:    args{typeName: "decimal", params: []string{"66"}},
+			wantCt:  model.ColumnType{},
+			wantErr: true,
+		},
+		{
+			name:   "set base for type REAL",
+			args:   args{typeName: "REAL"},
+			wantCt: model.ColumnType{Base: model.Double},
+		},
+		{
+			name:   "set digits and scale of FLOAT(M,D)",
+			args:   args{typeName: "float", params: []string{"7", "4"}},
+			wantCt: model.ColumnType{Base: model.Float, Param: 7, Scale: 4},
+		},
+		{
+			name:   "read FLOAT(p) up to 24 as FLOAT",
+			args:   args{typeName: "float", params: []string{"24"}},
+			wantCt: model.ColumnType{Base: model.Float},
+		},
+		{
+			name:   "read FLOAT(p) over 24 as DOUBLE",
+			args:   args{typeName: "float", params: []string{"25"}},
+			wantCt: model.ColumnType{Base: model.Double},
+		},
+		{
+			name:    "return error for the precision of FLOAT(p) out of range",
+			args:    args{typeName: "float", params: []string{"54"}},
+			wantCt:  model.ColumnType{},
+			wantErr: true,
+		},
+		{
+			name:    "return error for the scale of DOUBLE greater than the digits",
+			args:    args{typeName: "double", params: []string{"3", "4"}},
+			wantCt:  model.ColumnType{},
+			wantErr: true,
+		},
+		{
+			name:   "set the length 1 for type CHAR without length",
+			args:   args{typeName: "char"},
+			wantCt: model.ColumnType{Base: model.Char, Param: 1},
+		},
+		{
+			name:   "set the max size for type LONGBLOB",
+			args:   args{typeName: "longblob"},
+			wantCt: model.ColumnType{Base: model.Longblob, Param: 4294967295},
+		},
+		{
+			name:   "read TEXT(M) as the smallest type holding M characters",
+			args:   args{typeName: "text", params: []string{"70000"}},
+			wantCt: model.ColumnType{Base: model.Mediumtext, Param: 16777215},
+		},
+		{
+			name:    "return error for the length of BINARY out of range",
+			args:    args{typeName: "binary", params: []string{"256"}},
+			wantCt:  model.ColumnType{},
+			wantErr: true,
+		},
+		{
+			name:   "keep BIGINT as its own type",
+			args:   args{typeName: "BIGINT", params: []string{"20"}},
+			wantCt: model.ColumnType{Base: model.Bigint, Param: 20},
+		},
+		{
+			name:   "read INTEGER as INT",
+			args:   args{typeName: "integer"},
+			wantCt: model.ColumnType{Base: model.Int},
+		},
+		{
+			name:   "read BOOLEAN as TINYINT(1)",
+			args:   args{typeName: "BOOLEAN"},
+			wantCt: model.ColumnType{Base: model.Tinyint, Param: 1},
+		},
+		{
+			name:   "set the width 1 for type BIT without width",
+			args:   args{typeName: "bit"},
+			wantCt: model.ColumnType{Base: model.Bit, Param: 1},
+		},
+		{
+			name:    "return error for the width of BIT out of range",
+			args:    args{typeName: "bit", params: []string{"65"}},
+			wantCt:  model.ColumnType{},
+			wantErr: true,
+		},
+		{
+			name:   "set the members of ENUM",
+			args:   args{typeName: "ENUM", params: []string{"draft", "published"}},
+			wantCt: model.ColumnType{Base: model.Enum, Members: []string{"draft", "published"}},
+		},
+		{
+			name:    "return error for the duplicate members of SET",
+			args:    args{typeName: "set", params: []string{"a", "A"}},
+			wantCt:  model.ColumnType{},
+			wantErr: true,
+		},
+		{
+			name:    "return error for ENUM without members",
+			args:    args{typeName: "enum", params: []string{}},
+			wantCt:  model.ColumnType{},
+			wantErr: true,
+		},
+		{
+			name:   "set fractional seconds precision of DATETIME",
+			args:   args{typeName: "datetime", params: []string{"6"}},
+			wantCt: model.ColumnType{Base: model.Datetime, Param: 6},
+		},
+		{
+			name:   "set base for type DATE",
+			args:   args{typeName: "DATE"},
+			wantCt: model.ColumnType{Base: model.Date},
+		},
+		{
+			name:   "ignore the display width of YEAR(4)",
+			args:   args{typeName: "year", params: []string{"4"}},
+			wantCt: model.ColumnType{Base: model.Year},
+		},
+		{
+			name:    "return error for the fractional seconds precision of TIME out of range",
+			args:    args{typeName: "time", params: []string{"7"}},
+			wantCt:  model.ColumnType{},
+			wantErr: true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			gotCt, err := strToColumnType(tt.args.str)
+			gotCt, err := strToColumnType(tt.args.typeName, tt.args.params)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("strToColumnType() error = %v, wantErr %v", err, tt.wantErr)
 			}
@@ -0,0 +1,874 @@
+package file_driver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/canalun/sqloth/domain/model"
+)
+
+type parser struct {
+	tokens []token
+	pos    int
+	// foreignKeys is the foreign keys of all the tables, whose referenced tables may be defined after them
+	foreignKeys []foreignKey
+}
+
+type foreignKey struct {
+	table             model.TableName
+	columns           []model.ColumnName
+	referencedTable   model.TableName
+	referencedColumns []model.ColumnName
+	firstToken        token
+}
+
+// parseSchema reads the CREATE TABLE statements in the given DDL and returns the schema of them.
+// foreign keys added by ALTER TABLE statements are set to the tables defined before them.
+// the referenced tables and columns of the foreign keys are checked after all the statements, because they may be defined later.
+// the other statements (e.g. SET, INSERT, DROP TABLE) are skipped.
+func parseSchema(src string) (model.Schema, error) {
+	tokens, err := tokenize(src)
+	if err != nil {
+		return model.Schema{}, err
+	}
+	p := parser{tokens: tokens}
+
+	schema := model.Schema{}
+	for p.peek().kind != tokenEOF {
+		if p.accept(";") {
+			continue
+		}
+		if p.peek().is("CREATE") {
+			table, ok, err := p.parseCreateTable()
+			if err != nil {
+				return model.Schema{}, err
+			}
+			if ok {
+				schema.AddTable(table)
+			}
+			continue
+		}
+		if p.peek().is("ALTER") {
+			if err := p.parseAlterTable(&schema); err != nil {
+				return model.Schema{}, err
+			}
+			continue
+		}
+		p.skipStatement()
+	}
+	if err := p.checkForeignKeys(schema); err != nil {
+		return model.Schema{}, err
+	}
+	return schema, nil
+}
+
+// checkForeignKeys checks that the referenced tables and columns of the foreign keys are defined.
+// table names are compared exactly first and then case-insensitively, and column names case-insensitively as MySQL does.
+func (p *parser) checkForeignKeys(schema model.Schema) error {
+	for _, fk := range p.foreignKeys {
+		referenced, ok := lookupTable(schema, fk.referencedTable)
+		if !ok {
+			return p.errorf(fk.firstToken, "table %s referred to by the foreign key of table %s is not defined", fk.referencedTable, fk.table)
+		}
+		for _, columnName := range fk.referencedColumns {
+			if _, ok := lookupColumn(referenced, columnName); !ok {
+				return p.errorf(fk.firstToken, "column %s referred to by the foreign key of table %s is not defined in table %s", columnName, fk.table, referenced.Name)
+			}
+		}
+	}
+	return nil
+}
+
+func lookupTable(schema model.Schema, tableName model.TableName) (model.Table, bool) {
+	for _, t := range schema.Tables {
+		if t.Name == tableName {
+			return t, true
+		}
+	}
+	for _, t := range schema.Tables {
+		if strings.EqualFold(string(t.Name), string(tableName)) {
+			return t, true
+		}
+	}
+	return model.Table{}, false
+}
+
+func lookupColumn(table model.Table, columnName model.ColumnName) (int, bool) {
+	for i, c := range table.Columns {
+		if strings.EqualFold(string(c.Name), string(columnName)) {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+func (p *parser) next() token {
+	tok := p.peek()
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+// accept consumes the next token only if it is one of the given symbols or keywords.
+func (p *parser) accept(ss ...string) bool {
+	for _, s := range ss {
+		if p.peek().is(s) {
+			p.next()
+			return true
+		}
+	}
+	return false
+}
+
+func (p *parser) expect(s string) (token, error) {
+	tok := p.next()
+	if !tok.is(s) {
+		return token{}, p.errorf(tok, "expected %s but got %s", s, tok)
+	}
+	return tok, nil
+}
+
+func (p *parser) errorf(tok token, format string, a ...interface{}) error {
+	return newParseError(tok, nil, format, a...)
+}
+
+// skipStatement consumes tokens until the end of the current statement.
+func (p *parser) skipStatement() {
+	for {
+		tok := p.next()
+		if tok.kind == tokenEOF || tok.is(";") {
+			return
+		}
+	}
+}
+
+// skipParenthesized consumes tokens from "(" to its corresponding ")".
+func (p *parser) skipParenthesized() error {
+	open, err := p.expect("(")
+	if err != nil {
+		return err
+	}
+	depth := 1
+	for depth > 0 {
+		tok := p.next()
+		switch {
+		case tok.kind == tokenEOF:
+			return p.errorf(open, "unclosed parenthesis")
+		case tok.is("("):
+			depth++
+		case tok.is(")"):
+			depth--
+		}
+	}
+	return nil
+}
+
+// parseParenthesized reads tokens from "(" to its corresponding ")" and returns them as SQL text, e.g. "(uuid())".
+func (p *parser) parseParenthesized() (string, error) {
+	open, err := p.expect("(")
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	sb.WriteString("(")
+	prev := open
+	depth := 1
+	for depth > 0 {
+		tok := p.next()
+		switch {
+		case tok.kind == tokenEOF:
+			return "", p.errorf(open, "unclosed parenthesis")
+		case tok.is("("):
+			depth++
+		case tok.is(")"):
+			depth--
+		}
+		if needsSpace(prev, tok) {
+			sb.WriteString(" ")
+		}
+		sb.WriteString(sqlText(tok))
+		prev = tok
+	}
+	return sb.String(), nil
+}
+
+// parseDefault reads the expression of a DEFAULT clause, e.g. 'active', -1, CURRENT_TIMESTAMP(6), (uuid()).
+func (p *parser) parseDefault() (string, error) {
+	if p.peek().is("(") {
+		return p.parseParenthesized()
+	}
+	tok := p.next()
+	switch {
+	case tok.is("-") || tok.is("+"):
+		num := p.next()
+		if num.kind != tokenNumber {
+			return "", p.errorf(num, "expected number but got %s", num)
+		}
+		return tok.text + num.text, nil
+	case tok.kind == tokenString || tok.kind == tokenNumber:
+		return sqlText(tok), nil
+	case tok.kind == tokenWord:
+		switch {
+		case p.peek().is("("):
+			// e.g. CURRENT_TIMESTAMP(6), NOW()
+			args, err := p.parseParenthesized()
+			if err != nil {
+				return "", err
+			}
+			return tok.text + args, nil
+		case p.peek().kind == tokenString:
+			// e.g. b'1', _utf8mb4'a'
+			return tok.text + sqlText(p.next()), nil
+		default:
+			return tok.text, nil
+		}
+	default:
+		return "", p.errorf(tok, "expected default value but got %s", tok)
+	}
+}
+
+// sqlText returns the token as it is written in SQL, quoting and escaping string literals.
+func sqlText(tok token) string {
+	if tok.kind == tokenString {
+		return "'" + strings.NewReplacer(`\`, `\\`, "'", "''").Replace(tok.text) + "'"
+	}
+	return tok.String()
+}
+
+// needsSpace reports whether a space is put between the tokens in SQL text.
+// it puts no space inside parentheses, before commas and around dots, and between a function name and its arguments.
+func needsSpace(prev, tok token) bool {
+	switch {
+	case prev.is("(") || prev.is("."):
+		return false
+	case tok.is(")") || tok.is(",") || tok.is("."):
+		return false
+	case tok.is("("):
+		return !prev.isIdent()
+	default:
+		return true
+	}
+}
+
+// skipToDefinitionEnd consumes tokens until "," or ")" which ends the current definition in CREATE TABLE.
+// the "," or ")" itself is not consumed. it also stops at ";" so that the caller can report the missing ")".
+func (p *parser) skipToDefinitionEnd() error {
+	for {
+		tok := p.peek()
+		switch {
+		case tok.kind == tokenEOF:
+			return p.errorf(tok, "unexpected end of file in table definition")
+		case tok.is(",") || tok.is(")") || tok.is(";"):
+			return nil
+		case tok.is("("):
+			if err := p.skipParenthesized(); err != nil {
+				return err
+			}
+		default:
+			p.next()
+		}
+	}
+}
+
+func (p *parser) parseIdent() (string, error) {
+	tok := p.next()
+	if !tok.isIdent() {
+		return "", p.errorf(tok, "expected identifier but got %s", tok)
+	}
+	return tok.text, nil
+}
+
+// parseTableName reads a table name, dropping the database name if exists (e.g. `db`.`table`).
+func (p *parser) parseTableName() (model.TableName, error) {
+	name, err := p.parseIdent()
+	if err != nil {
+		return "", err
+	}
+	if p.accept(".") {
+		name, err = p.parseIdent()
+		if err != nil {
+			return "", err
+		}
+	}
+	return model.TableName(name), nil
+}
+
+// parseCreateTable reads a CREATE TABLE statement.
+// ok is false when the statement is not a CREATE TABLE with column definitions (e.g. CREATE TABLE ... LIKE, CREATE VIEW).
+func (p *parser) parseCreateTable() (table model.Table, ok bool, err error) {
+	if _, err := p.expect("CREATE"); err != nil {
+		return model.Table{}, false, err
+	}
+	p.accept("TEMPORARY")
+	if !p.accept("TABLE") {
+		p.skipStatement()
+		return model.Table{}, false, nil
+	}
+	if p.accept("IF") {
+		if _, err := p.expect("NOT"); err != nil {
+			return model.Table{}, false, err
+		}
+		if _, err := p.expect("EXISTS"); err != nil {
+			return model.Table{}, false, err
+		}
+	}
+
+	tableNameToken := p.peek()
+	tableName, err := p.parseTableName()
+	if err != nil {
+		return model.Table{}, false, err
+	}
+	if !p.peek().is("(") {
+		p.skipStatement()
+		return model.Table{}, false, nil
+	}
+	p.next()
+
+	table = model.NewTable(tableName, []model.Column{})
+	foreignKeys := []foreignKey{}
+	for {
+		tok := p.peek()
+		switch {
+		case tok.is("CONSTRAINT"):
+			p.next()
+			if !p.peek().is("PRIMARY") && !p.peek().is("UNIQUE") && !p.peek().is("FOREIGN") && !p.peek().is("CHECK") {
+				if _, err := p.parseIdent(); err != nil {
+					return model.Table{}, false, err
+				}
+			}
+			continue
+		case tok.is("FOREIGN"):
+			fk, err := p.parseForeignKey()
+			if err != nil {
+				return model.Table{}, false, err
+			}
+			if err := p.skipToDefinitionEnd(); err != nil {
+				return model.Table{}, false, err
+			}
+			foreignKeys = append(foreignKeys, fk)
+		case tok.is("PRIMARY"):
+			key, err := p.parsePrimaryKey()
+			if err != nil {
+				return model.Table{}, false, err
+			}
+			table.SetPrimaryKey(key)
+		case tok.is("UNIQUE"):
+			key, err := p.parseUniqueKey()
+			if err != nil {
+				return model.Table{}, false, err
+			}
+			table.AddUniqueKey(key)
+		case tok.is("KEY"), tok.is("INDEX"), tok.is("FULLTEXT"), tok.is("SPATIAL"), tok.is("CHECK"):
+			if err := p.skipToDefinitionEnd(); err != nil {
+				return model.Table{}, false, err
+			}
+		default:
+			column, primary, unique, err := p.parseColumnDefinition(tableName)
+			if err != nil {
+				return model.Table{}, false, err
+			}
+			table.AddColumns(column)
+			if primary {
+				table.SetPrimaryKey(model.Key{column.Name})
+			}
+			if unique {
+				table.AddUniqueKey(model.Key{column.Name})
+			}
+		}
+
+		if p.accept(",") {
+			continue
+		}
+		if _, err := p.expect(")"); err != nil {
+			return model.Table{}, false, err
+		}
+		break
+	}
+	if err := p.parseTableOptions(&table); err != nil {
+		return model.Table{}, false, err
+	}
+
+	for _, fk := range foreignKeys {
+		if err := setForeignKey(&table, fk); err != nil {
+			return model.Table{}, false, p.errorf(fk.firstToken, "%v", err)
+		}
+		fk.table = table.Name
+		p.foreignKeys = append(p.foreignKeys, fk)
+	}
+	for _, key := range table.Keys() {
+		for _, columnName := range key {
+			if _, ok := table.Column(columnName); !ok {
+				return model.Table{}, false, p.errorf(tableNameToken, "column %s of the key is not defined in table %s", columnName, table.Name)
+			}
+		}
+	}
+	// the columns of the primary key are NOT NULL implicitly
+	for i := range table.Columns {
+		for _, columnName := range table.PrimaryKey {
+			if table.Columns[i].Name == columnName {
+				table.Columns[i].SetNotNull()
+			}
+		}
+	}
+	return table, true, nil
+}
+
+// parseTableOptions reads the table options after the column definitions to the end of the statement.
+// only AUTO_INCREMENT is kept, and the others such as ENGINE=InnoDB are not needed.
+func (p *parser) parseTableOptions(table *model.Table) error {
+	collation := ""
+	for {
+		tok := p.peek()
+		switch {
+		case tok.kind == tokenEOF || tok.is(";"):
+			p.next()
+			if collation != "" {
+				table.SetDefaultCollation(collation)
+			}
+			return nil
+		case tok.is("AUTO_INCREMENT"):
+			if err := p.parseAutoIncrement(table); err != nil {
+				return err
+			}
+		case tok.is("COLLATE"), tok.is("CHARSET"), tok.is("CHARACTER"):
+			// e.g. DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_bin
+			c, err := p.parseCollation()
+			if err != nil {
+				return err
+			}
+			if c != "" {
+				collation = c
+			}
+		default:
+			p.next()
+		}
+	}
+}
+
+// parseCollation reads "COLLATE [=] name" or "{CHARACTER SET | CHARSET} [=] name" and returns the collation.
+// a character set gives no collation except binary, because the default collations of the others are all case-insensitive.
+func (p *parser) parseCollation() (string, error) {
+	isCollation := p.accept("COLLATE")
+	if !isCollation {
+		p.next()
+		p.accept("SET")
+	}
+	p.accept("=")
+	tok := p.next()
+	if !tok.isIdent() && tok.kind != tokenString {
+		return "", p.errorf(tok, "expected the name of the collation or the character set but got %s", tok)
+	}
+	switch {
+	case isCollation:
+		return tok.text, nil
+	case strings.EqualFold(tok.text, "binary"):
+		return "binary", nil
+	default:
+		return "", nil
+	}
+}
+
+// parseAutoIncrement reads "AUTO_INCREMENT [=] value" of a table option, which is the value of the first row inserted.
+func (p *parser) parseAutoIncrement(table *model.Table) error {
+	if _, err := p.expect("AUTO_INCREMENT"); err != nil {
+		return err
+	}
+	p.accept("=")
+	num := p.next()
+	start, err := strconv.ParseInt(num.text, 10, 64)
+	if num.kind != tokenNumber || err != nil || start < 0 {
+		return p.errorf(num, "expected the value of AUTO_INCREMENT but got %s", num)
+	}
+	table.SetAutoIncrementStart(start)
+	return nil
+}
+
+// parseColumnDefinition reads a column definition such as "`name` varchar(255) NOT NULL DEFAULT 'a'".
+// primary and unique tell whether the column is defined as PRIMARY KEY or UNIQUE KEY by itself.
+func (p *parser) parseColumnDefinition(tableName model.TableName) (column model.Column, primary, unique bool, err error) {
+	_columnName, err := p.parseIdent()
+	if err != nil {
+		return model.Column{}, false, false, err
+	}
+	columnName := model.ColumnName(_columnName)
+
+	typeToken := p.next()
+	if typeToken.kind != tokenWord {
+		return model.Column{}, false, false, p.errorf(typeToken, "expected data type but got %s", typeToken)
+	}
+	if typeToken.is("DOUBLE") {
+		// DOUBLE PRECISION is DOUBLE
+		p.accept("PRECISION")
+	}
+	params, err := p.parseTypeParams()
+	if err != nil {
+		return model.Column{}, false, false, err
+	}
+	columnType, err := strToColumnType(typeToken.text, params)
+	if err != nil {
+		return model.Column{}, false, false, newParseError(typeToken, err, "%v", err)
+	}
+
+	column = model.NewColumn(model.NewColumnFullName(tableName, columnName), columnType)
+	for {
+		tok := p.peek()
+		switch {
+		case tok.kind == tokenEOF:
+			return model.Column{}, false, false, p.errorf(tok, "unexpected end of file in column definition")
+		case tok.is(",") || tok.is(")") || tok.is(";"):
+			return column, primary, unique, nil
+		case tok.is("("):
+			// e.g. DEFAULT (expr), CHECK (expr)
+			if err := p.skipParenthesized(); err != nil {
+				return model.Column{}, false, false, err
+			}
+		case tok.is("UNSIGNED"):
+			p.next()
+			column.SetUnsigned(true)
+		case tok.is("COLLATE"), tok.is("CHARSET"), tok.is("CHARACTER"):
+			// e.g. `code` varchar(8) CHARACTER SET utf8mb4 COLLATE utf8mb4_bin
+			collation, err := p.parseCollation()
+			if err != nil {
+				return model.Column{}, false, false, err
+			}
+			if collation != "" {
+				column.SetCollation(collation)
+			}
+		case tok.is("BINARY"):
+			// the BINARY attribute is the binary collation of the character set, e.g. `code` varchar(8) BINARY
+			p.next()
+			column.SetCollation("binary")
+		case tok.is("AUTO_INCREMENT"):
+			p.next()
+			column.SetAutoIncrement()
+		case tok.is("GENERATED"), tok.is("AS"):
+			// e.g. `full_name` varchar(64) GENERATED ALWAYS AS (concat(first, ' ', last)) VIRTUAL, whose expression is skipped as "("
+			p.next()
+			p.accept("ALWAYS")
+			p.accept("AS")
+			column.SetGenerated()
+		case tok.is("SRID"):
+			// e.g. `location` point NOT NULL SRID 4326
+			p.next()
+			num := p.next()
+			srid, err := strconv.Atoi(num.text)
+			if num.kind != tokenNumber || err != nil || srid < 0 {
+				return model.Column{}, false, false, p.errorf(num, "expected SRID but got %s", num)
+			}
+			column.SetSRID(srid)
+		case tok.is("NOT"):
+			p.next()
+			if p.accept("NULL") {
+				column.SetNotNull()
+			}
+		case tok.is("DEFAULT"):
+			p.next()
+			expr, err := p.parseDefault()
+			if err != nil {
+				return model.Column{}, false, false, err
+			}
+			column.SetDefault(expr)
+		case tok.is("REFERENCES"):
+			// e.g. `user_id` int REFERENCES `user` (`id`)
+			referencedTable, referencedColumns, err := p.parseReferences()
+			if err != nil {
+				return model.Column{}, false, false, err
+			}
+			if len(referencedColumns) != 1 {
+				return model.Column{}, false, false, p.errorf(tok, "the foreign key of column %s must refer to one column", columnName)
+			}
+			column.SetConstraint(model.NewConstraint(referencedTable, referencedColumns[0]))
+			p.foreignKeys = append(p.foreignKeys, foreignKey{
+				table:             tableName,
+				columns:           []model.ColumnName{columnName},
+				referencedTable:   referencedTable,
+				referencedColumns: referencedColumns,
+				firstToken:        tok,
+			})
+		case tok.is("PRIMARY"):
+			p.next()
+			p.accept("KEY")
+			primary = true
+		case tok.is("UNIQUE"):
+			p.next()
+			p.accept("KEY")
+			unique = true
+		case tok.is("KEY"):
+			// KEY alone in a column definition means PRIMARY KEY
+			p.next()
+			primary = true
+		default:
+			p.next()
+		}
+	}
+}
+
+// parseTypeParams reads the parameters of a data type, e.g. (255) of varchar(255), if exist.
+func (p *parser) parseTypeParams() ([]string, error) {
+	params := []string{}
+	if !p.accept("(") {
+		return params, nil
+	}
+	for {
+		tok := p.next()
+		if tok.kind != tokenNumber && tok.kind != tokenString {
+			return nil, p.errorf(tok, "unexpected %s in data type parameters", tok)
+		}
+		params = append(params, tok.text)
+		if p.accept(",") {
+			continue
+		}
+		if _, err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return params, nil
+	}
+}
+
+// parsePrimaryKey reads "PRIMARY KEY [USING BTREE] (col, ...) [index_option]".
+func (p *parser) parsePrimaryKey() (model.Key, error) {
+	if _, err := p.expect("PRIMARY"); err != nil {
+		return nil, err
+	}
+	if _, err := p.expect("KEY"); err != nil {
+		return nil, err
+	}
+	p.skipIndexType()
+	columns, err := p.parseKeyParts()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.skipToDefinitionEnd(); err != nil {
+		return nil, err
+	}
+	return model.Key(columns), nil
+}
+
+// parseUniqueKey reads "UNIQUE [KEY | INDEX] [index_name] [USING BTREE] (col, ...) [index_option]".
+func (p *parser) parseUniqueKey() (model.Key, error) {
+	if _, err := p.expect("UNIQUE"); err != nil {
+		return nil, err
+	}
+	p.accept("KEY", "INDEX")
+	if p.peek().isIdent() && !p.peek().is("USING") {
+		p.next()
+	}
+	p.skipIndexType()
+	columns, err := p.parseKeyParts()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.skipToDefinitionEnd(); err != nil {
+		return nil, err
+	}
+	return model.Key(columns), nil
+}
+
+// skipIndexType consumes "USING BTREE" or "USING HASH" if exists.
+func (p *parser) skipIndexType() {
+	if p.accept("USING") {
+		p.next()
+	}
+}
+
+// parseForeignKey reads "FOREIGN KEY [index_name] (col, ...) REFERENCES table (col, ...) [ON DELETE ...]".
+// the tokens after it are left to the caller, which are different in CREATE TABLE and ALTER TABLE.
+func (p *parser) parseForeignKey() (foreignKey, error) {
+	first, err := p.expect("FOREIGN")
+	if err != nil {
+		return foreignKey{}, err
+	}
+	if _, err := p.expect("KEY"); err != nil {
+		return foreignKey{}, err
+	}
+	if p.peek().isIdent() {
+		p.next()
+	}
+	columns, err := p.parseKeyParts()
+	if err != nil {
+		return foreignKey{}, err
+	}
+	referencedTable, referencedColumns, err := p.parseReferences()
+	if err != nil {
+		return foreignKey{}, err
+	}
+	if len(columns) != len(referencedColumns) {
+		return foreignKey{}, p.errorf(first, "the number of referencing and referenced columns of the foreign key are different")
+	}
+	return foreignKey{
+		columns:           columns,
+		referencedTable:   referencedTable,
+		referencedColumns: referencedColumns,
+		firstToken:        first,
+	}, nil
+}
+
+// parseReferences reads "REFERENCES table (col, ...) [MATCH ...] [ON DELETE ...] [ON UPDATE ...]".
+// MATCH, ON DELETE and ON UPDATE are not needed, so they are skipped.
+func (p *parser) parseReferences() (model.TableName, []model.ColumnName, error) {
+	if _, err := p.expect("REFERENCES"); err != nil {
+		return "", nil, err
+	}
+	referencedTable, err := p.parseTableName()
+	if err != nil {
+		return "", nil, err
+	}
+	referencedColumns, err := p.parseKeyParts()
+	if err != nil {
+		return "", nil, err
+	}
+	for {
+		switch {
+		case p.accept("MATCH"):
+			// FULL, PARTIAL or SIMPLE
+			p.next()
+		case p.accept("ON"):
+			// DELETE or UPDATE, followed by RESTRICT, CASCADE, SET NULL, SET DEFAULT or NO ACTION
+			p.next()
+			p.accept("SET", "NO")
+			p.next()
+		default:
+			return referencedTable, referencedColumns, nil
+		}
+	}
+}
+
+// parseAlterTable reads an ALTER TABLE statement and sets the foreign keys and the AUTO_INCREMENT option by it to the table in the schema.
+// the other alterations are skipped.
+func (p *parser) parseAlterTable(schema *model.Schema) error {
+	if _, err := p.expect("ALTER"); err != nil {
+		return err
+	}
+	p.accept("ONLINE")
+	p.accept("IGNORE")
+	if !p.accept("TABLE") {
+		p.skipStatement()
+		return nil
+	}
+	tableNameToken := p.peek()
+	tableName, err := p.parseTableName()
+	if err != nil {
+		return err
+	}
+	var table *model.Table
+	for i := range schema.Tables {
+		if schema.Tables[i].Name == tableName {
+			table = &schema.Tables[i]
+		}
+	}
+
+	for {
+		if p.peek().is("AUTO_INCREMENT") {
+			// e.g. ALTER TABLE `user` AUTO_INCREMENT=5
+			if table == nil {
+				return p.errorf(tableNameToken, "table %s of AUTO_INCREMENT is not defined", tableName)
+			}
+			if err := p.parseAutoIncrement(table); err != nil {
+				return err
+			}
+		}
+		if p.accept("ADD") {
+			if p.accept("CONSTRAINT") && !p.peek().is("FOREIGN") {
+				if _, err := p.parseIdent(); err != nil {
+					return err
+				}
+			}
+			if p.peek().is("FOREIGN") {
+				fk, err := p.parseForeignKey()
+				if err != nil {
+					return err
+				}
+				if table == nil {
+					return p.errorf(tableNameToken, "table %s of the foreign key is not defined", tableName)
+				}
+				if err := setForeignKey(table, fk); err != nil {
+					return p.errorf(fk.firstToken, "%v", err)
+				}
+				fk.table = table.Name
+				p.foreignKeys = append(p.foreignKeys, fk)
+			}
+		}
+		if err := p.skipToAlterationEnd(); err != nil {
+			return err
+		}
+		if p.accept(",") {
+			continue
+		}
+		p.skipStatement()
+		return nil
+	}
+}
+
+// skipToAlterationEnd consumes tokens until "," or ";" which ends the current alteration in ALTER TABLE.
+// the "," or ";" itself is not consumed.
+func (p *parser) skipToAlterationEnd() error {
+	for {
+		tok := p.peek()
+		switch {
+		case tok.kind == tokenEOF || tok.is(",") || tok.is(";"):
+			return nil
+		case tok.is("("):
+			if err := p.skipParenthesized(); err != nil {
+				return err
+			}
+		default:
+			p.next()
+		}
+	}
+}
+
+// parseKeyParts reads a list of columns such as (`id`, `name`(10) DESC).
+func (p *parser) parseKeyParts() ([]model.ColumnName, error) {
+	if _, err := p.expect("("); err != nil {
+		return nil, err
+	}
+	columns := []model.ColumnName{}
+	for {
+		name, err := p.parseIdent()
+		if err != nil {
+			return nil, err
+		}
+		columns = append(columns, model.ColumnName(name))
+		// prefix length such as `name`(10)
+		if p.peek().is("(") {
+			if err := p.skipParenthesized(); err != nil {
+				return nil, err
+			}
+		}
+		p.accept("ASC", "DESC")
+		if p.accept(",") {
+			continue
+		}
+		if _, err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return columns, nil
+	}
+}
+
+// setForeignKey sets the constraints of the foreign key to its columns.
+// every column of a composite foreign key knows all the columns of the key, so that they refer to the same row.
+// the columns are compared case-insensitively, and the names as defined in the table are used for the constraints.
+func setForeignKey(table *model.Table, fk foreignKey) error {
+	indexes := make([]int, len(fk.columns))
+	columns := make([]model.ColumnName, len(fk.columns))
+	for i, columnName := range fk.columns {
+		j, ok := lookupColumn(*table, columnName)
+		if !ok {
+			return fmt.Errorf("column %s of the foreign key is not defined in table %s", columnName, table.Name)
+		}
+		indexes[i] = j
+		columns[i] = table.Columns[j].Name
+	}
+	constraints := model.NewForeignKeyConstraints(fk.referencedTable, columns, fk.referencedColumns)
+	for i, j := range indexes {
+		table.Columns[j].SetConstraint(constraints[i])
+	}
+	return nil
+}
@@ -0,0 +1,455 @@
+package file_driver
+
+import (
+	"testing"
+
+	"github.com/canalun/sqloth/domain/model"
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_parseSchema(t *testing.T) {
+	type args struct {
+		src string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    model.Schema
+		wantErr bool
+	}{
+		{
+			name: "parse column definitions spanning multiple lines, indented by tabs and with comments",
+			args: args{src: "-- dumped schema\n" +
+				"/*!40101 SET NAMES utf8 */;\n" +
+				"DROP TABLE IF EXISTS `user`;\n" +
+				"CREATE TABLE IF NOT EXISTS `db`.`user` (\n" +
+				"\t`id` int(10)\n" +
+				"\t\tUNSIGNED -- the id, never reused\n" +
+				"\t\tAUTO_INCREMENT,\n" +
+				"\t`nick, name` varchar(32) NOT NULL COMMENT 'a `quoted`, commented column', /* trailing comment */\n" +
+				"\tbio text NULL DEFAULT ('')\n" +
+				");\n",
+			},
+			want: model.Schema{
+				Tables: []model.Table{
+					{
+						Name: "user",
+						Columns: []model.Column{
+							{
+								Name:     "id",
+								FullName: "user.id",
+								Type: model.ColumnType{
+									Base:  model.Int,
+									Param: model.ColumnTypeParam(10),
+								},
+								AutoIncrement: true,
+								Unsigned:      true,
+							},
+							{
+								Name:     "nick, name",
+								FullName: "user.nick, name",
+								Type: model.ColumnType{
+									Base:  model.Varchar,
+									Param: model.ColumnTypeParam(32),
+								},
+								NotNull: true,
+							},
+							{
+								Name:     "bio",
+								FullName: "user.bio",
+								Type: model.ColumnType{
+									Base:  model.Text,
+									Param: model.ColumnTypeParam(65535),
+								},
+								Default:    "('')",
+								HasDefault: true,
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "set foreign key constraints to the referencing columns",
+			args: args{src: "CREATE TABLE `a` (`id` int, `name` varchar(8));\n" +
+				"CREATE TABLE `b` (\n" +
+				"  `a_id` int,\n" +
+				"  `a_name` varchar(8),\n" +
+				"  KEY `idx` (`a_name`(4) DESC),\n" +
+				"  CONSTRAINT `fk` FOREIGN KEY (`a_id`, `a_name`) REFERENCES `a` (`id`, `name`) ON DELETE CASCADE\n" +
+				") ENGINE=InnoDB;",
+			},
+			want: model.Schema{
+				Tables: []model.Table{
+					{
+						Name: "a",
+						Columns: []model.Column{
+							{Name: "id", FullName: "a.id", Type: model.ColumnType{Base: model.Int}},
+							{Name: "name", FullName: "a.name", Type: model.ColumnType{Base: model.Varchar, Param: 8}},
+						},
+					},
+					{
+						Name: "b",
+						Columns: []model.Column{
+							{
+								Name:        "a_id",
+								FullName:    "b.a_id",
+								Type:        model.ColumnType{Base: model.Int},
+								Constraints: []model.Constraint{{TableName: "a", ColumnName: "id", Columns: []model.ColumnName{"a_id", "a_name"}, ReferencedColumns: []model.ColumnName{"id", "name"}}},
+							},
+							{
+								Name:        "a_name",
+								FullName:    "b.a_name",
+								Type:        model.ColumnType{Base: model.Varchar, Param: 8},
+								Constraints: []model.Constraint{{TableName: "a", ColumnName: "name", Columns: []model.ColumnName{"a_id", "a_name"}, ReferencedColumns: []model.ColumnName{"id", "name"}}},
+							},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "set foreign keys without names, defined by columns and added by ALTER TABLE",
+			args: args{src: "CREATE TABLE `a` (`id` int, `x` int);\n" +
+				"CREATE TABLE `b` (\n" +
+				"  `id` int,\n" +
+				"  `a_id` int REFERENCES `a` (`id`) ON DELETE SET NULL ON UPDATE CASCADE,\n" +
+				"  `a_x` int,\n" +
+				"  `b_id` int,\n" +
+				"  FOREIGN KEY (`a_x`) REFERENCES `a` (`x`) ON DELETE NO ACTION\n" +
+				");\n" +
+				"ALTER TABLE `b` ADD INDEX `idx` (`b_id`), ADD CONSTRAINT `fk_b` FOREIGN KEY (`b_id`) REFERENCES `b` (`id`) ON DELETE CASCADE;\n" +
+				"ALTER TABLE `db`.`b` ADD FOREIGN KEY `fk_a` (`a_id`, `a_x`) REFERENCES `a` (`id`, `x`), ALGORITHM=INPLACE\n",
+			},
+			want: model.Schema{
+				Tables: []model.Table{
+					{
+						Name: "a",
+						Columns: []model.Column{
+							{Name: "id", FullName: "a.id", Type: model.ColumnType{Base: model.Int}},
+							{Name: "x", FullName: "a.x", Type: model.ColumnType{Base: model.Int}},
+						},
+					},
+					{
+						Name: "b",
+						Columns: []model.Column{
+							{Name: "id", FullName: "b.id", Type: model.ColumnType{Base: model.Int}},
+							{
+								Name:     "a_id",
+								FullName: "b.a_id",
+								Type:     model.ColumnType{Base: model.Int},
+								Constraints: []model.Constraint{
+									{TableName: "a", ColumnName: "id"},
+									{TableName: "a", ColumnName: "id", Columns: []model.ColumnName{"a_id", "a_x"}, ReferencedColumns: []model.ColumnName{"id", "x"}},
+								},
+							},
+							{
+								Name:     "a_x",
+								FullName: "b.a_x",
+								Type:     model.ColumnType{Base: model.Int},
+								Constraints: []model.Constraint{
+									{TableName: "a", ColumnName: "x"},
+									{TableName: "a", ColumnName: "x", Columns: []model.ColumnName{"a_id", "a_x"}, ReferencedColumns: []model.ColumnName{"id", "x"}},
+								},
+							},
+							{Name: "b_id", FullName: "b.b_id", Type: model.ColumnType{Base: model.Int}, Constraints: []model.Constraint{{TableName: "b", ColumnName: "id"}}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "set primary keys and unique keys defined by columns and by tables",
+			args: args{src: "CREATE TABLE `a` (\n" +
+				"  `id` int PRIMARY KEY,\n" +
+				"  `code` varchar(8) UNIQUE,\n" +
+				"  `x` int,\n" +
+				"  `y` int,\n" +
+				"  UNIQUE INDEX `xy` USING BTREE (`x`, `y`),\n" +
+				"  CONSTRAINT `u_code` UNIQUE KEY (`code`)\n" +
+				");\n" +
+				"CREATE TABLE `b` (`a_id` int, `seq` int, PRIMARY KEY (`a_id`, `seq`));",
+			},
+			want: model.Schema{
+				Tables: []model.Table{
+					{
+						Name: "a",
+						Columns: []model.Column{
+							{Name: "id", FullName: "a.id", Type: model.ColumnType{Base: model.Int}, NotNull: true},
+							{Name: "code", FullName: "a.code", Type: model.ColumnType{Base: model.Varchar, Param: 8}},
+							{Name: "x", FullName: "a.x", Type: model.ColumnType{Base: model.Int}},
+							{Name: "y", FullName: "a.y", Type: model.ColumnType{Base: model.Int}},
+						},
+						PrimaryKey: model.Key{"id"},
+						UniqueKeys: []model.Key{{"code"}, {"x", "y"}},
+					},
+					{
+						Name: "b",
+						Columns: []model.Column{
+							{Name: "a_id", FullName: "b.a_id", Type: model.ColumnType{Base: model.Int}, NotNull: true},
+							{Name: "seq", FullName: "b.seq", Type: model.ColumnType{Base: model.Int}, NotNull: true},
+						},
+						PrimaryKey: model.Key{"a_id", "seq"},
+					},
+				},
+			},
+		},
+		{
+			name: "keep the expressions of DEFAULT clauses",
+			args: args{src: "CREATE TABLE `a` (\n" +
+				"  `status` varchar(20) NOT NULL DEFAULT 'it''s',\n" +
+				"  `n` int DEFAULT -1 COMMENT 'negative',\n" +
+				"  `created_at` datetime DEFAULT NOW() ON UPDATE NOW(),\n" +
+				"  `uuid` varchar(36) DEFAULT (uuid()),\n" +
+				"  `memo` json DEFAULT (JSON_ARRAY(1, 'b'))\n" +
+				");",
+			},
+			want: model.Schema{
+				Tables: []model.Table{
+					{
+						Name: "a",
+						Columns: []model.Column{
+							{Name: "status", FullName: "a.status", Type: model.ColumnType{Base: model.Varchar, Param: 20}, NotNull: true, Default: "'it''s'", HasDefault: true},
+							{Name: "n", FullName: "a.n", Type: model.ColumnType{Base: model.Int}, Default: "-1", HasDefault: true},
+							{Name: "created_at", FullName: "a.created_at", Type: model.ColumnType{Base: model.Datetime}, Default: "NOW()", HasDefault: true},
+							{Name: "uuid", FullName: "a.uuid", Type: model.ColumnType{Base: model.Varchar, Param: 36}, Default: "(uuid())", HasDefault: true},
+							{Name: "memo", FullName: "a.memo", Type: model.ColumnType{Base: model.Json}, Default: "(JSON_ARRAY(1, 'b'))", HasDefault: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "parse the members of ENUM and SET",
+			args: args{src: "CREATE TABLE `a` (\n" +
+				"  `status` enum('draft','it''s published') NOT NULL DEFAULT 'draft',\n" +
+				"  `tags` set('a', 'b') CHARACTER SET utf8mb4\n" +
+				");",
+			},
+			want: model.Schema{
+				Tables: []model.Table{
+					{
+						Name: "a",
+						Columns: []model.Column{
+							{Name: "status", FullName: "a.status", Type: model.ColumnType{Base: model.Enum, Members: []string{"draft", "it's published"}}, NotNull: true, Default: "'draft'", HasDefault: true},
+							{Name: "tags", FullName: "a.tags", Type: model.ColumnType{Base: model.Set, Members: []string{"a", "b"}}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "parse spatial types and their SRID attributes",
+			args: args{src: "CREATE TABLE `store` (\n" +
+				"  `location` point NOT NULL SRID 4326,\n" +
+				"  `area` polygon,\n" +
+				"  SPATIAL INDEX (`location`)\n" +
+				");",
+			},
+			want: model.Schema{
+				Tables: []model.Table{
+					{
+						Name: "store",
+						Columns: []model.Column{
+							{Name: "location", FullName: "store.location", Type: model.ColumnType{Base: model.Point}, NotNull: true, SRID: 4326},
+							{Name: "area", FullName: "store.area", Type: model.ColumnType{Base: model.Polygon}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "mark generated columns",
+			args: args{src: "CREATE TABLE `user` (\n" +
+				"  `first` varchar(8),\n" +
+				"  `full` varchar(16) GENERATED ALWAYS AS (concat(`first`, ' ', 'x')) VIRTUAL,\n" +
+				"  `len` int AS (char_length(`first`)) STORED NOT NULL\n" +
+				");"},
+			want: model.Schema{
+				Tables: []model.Table{
+					{
+						Name: "user",
+						Columns: []model.Column{
+							{Name: "first", FullName: "user.first", Type: model.ColumnType{Base: model.Varchar, Param: 8}},
+							{Name: "full", FullName: "user.full", Type: model.ColumnType{Base: model.Varchar, Param: 16}, Generated: true},
+							{Name: "len", FullName: "user.len", Type: model.ColumnType{Base: model.Int}, Generated: true, NotNull: true},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "set the AUTO_INCREMENT table option to the auto increment column",
+			args: args{src: "CREATE TABLE `a` (`id` int AUTO_INCREMENT, `name` varchar(8), PRIMARY KEY (`id`)) ENGINE=InnoDB AUTO_INCREMENT=5 DEFAULT CHARSET=utf8mb4 COMMENT='AUTO_INCREMENT=9';\n" +
+				"CREATE TABLE `b` (`id` bigint AUTO_INCREMENT PRIMARY KEY);\n" +
+				"ALTER TABLE `b` ADD INDEX (`id`), AUTO_INCREMENT = 7;"},
+			want: model.Schema{
+				Tables: []model.Table{
+					{
+						Name: "a",
+						Columns: []model.Column{
+							{Name: "id", FullName: "a.id", Type: model.ColumnType{Base: model.Int}, AutoIncrement: true, AutoIncrementStart: 5, NotNull: true},
+							{Name: "name", FullName: "a.name", Type: model.ColumnType{Base: model.Varchar, Param: 8}},
+						},
+						PrimaryKey: model.Key{"id"},
+					},
+					{
+						Name: "b",
+						Columns: []model.Column{
+							{Name: "id", FullName: "b.id", Type: model.ColumnType{Base: model.Bigint}, AutoIncrement: true, AutoIncrementStart: 7, NotNull: true},
+						},
+						PrimaryKey: model.Key{"id"},
+					},
+				},
+			},
+		},
+		{
+			name: "set the collations of the columns and the table to the string columns",
+			args: args{src: "CREATE TABLE `a` (\n" +
+				"  `id` int,\n" +
+				"  `ci` varchar(8),\n" +
+				"  `cs` varchar(8) CHARACTER SET utf8mb4 COLLATE utf8mb4_0900_as_cs,\n" +
+				"  `bin` char(2) BINARY,\n" +
+				"  `raw` text CHARSET binary\n" +
+				") ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_0900_ai_ci;"},
+			want: model.Schema{
+				Tables: []model.Table{
+					{
+						Name: "a",
+						Columns: []model.Column{
+							{Name: "id", FullName: "a.id", Type: model.ColumnType{Base: model.Int}},
+							{Name: "ci", FullName: "a.ci", Type: model.ColumnType{Base: model.Varchar, Param: 8}, Collation: "utf8mb4_0900_ai_ci"},
+							{Name: "cs", FullName: "a.cs", Type: model.ColumnType{Base: model.Varchar, Param: 8}, Collation: "utf8mb4_0900_as_cs"},
+							{Name: "bin", FullName: "a.bin", Type: model.ColumnType{Base: model.Char, Param: 2}, Collation: "binary"},
+							{Name: "raw", FullName: "a.raw", Type: model.ColumnType{Base: model.Text, Param: 65535}, Collation: "binary"},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "resolve foreign keys to the tables defined after them and the columns in different cases",
+			args: args{src: "CREATE TABLE `a` (\n" +
+				"  `b_id` int,\n" +
+				"  `b_x` int,\n" +
+				"  FOREIGN KEY (`B_ID`, `b_x`) REFERENCES `b` (`ID`, `x`)\n" +
+				");\n" +
+				"CREATE TABLE `b` (`id` int, `x` int);"},
+			want: model.Schema{
+				Tables: []model.Table{
+					{
+						Name: "a",
+						Columns: []model.Column{
+							{
+								Name:     "b_id",
+								FullName: "a.b_id",
+								Type:     model.ColumnType{Base: model.Int},
+								Constraints: []model.Constraint{
+									{TableName: "b", ColumnName: "ID", Columns: []model.ColumnName{"b_id", "b_x"}, ReferencedColumns: []model.ColumnName{"ID", "x"}},
+								},
+							},
+							{
+								Name:     "b_x",
+								FullName: "a.b_x",
+								Type:     model.ColumnType{Base: model.Int},
+								Constraints: []model.Constraint{
+									{TableName: "b", ColumnName: "x", Columns: []model.ColumnName{"b_id", "b_x"}, ReferencedColumns: []model.ColumnName{"ID", "x"}},
+								},
+							},
+						},
+					},
+					{
+						Name: "b",
+						Columns: []model.Column{
+							{Name: "id", FullName: "b.id", Type: model.ColumnType{Base: model.Int}},
+							{Name: "x", FullName: "b.x", Type: model.ColumnType{Base: model.Int}},
+						},
+					},
+				},
+			},
+		},
+		{
+			name: "skip CREATE statements without column definitions",
+			args: args{src: "CREATE TABLE `a` LIKE `b`;\nCREATE VIEW `v` AS SELECT 1;"},
+			want: model.Schema{},
+		},
+		{
+			name:    "return error for unregistered data type",
+			args:    args{src: "CREATE TABLE `a` (`id` unknowntype);"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for SRID without number",
+			args:    args{src: "CREATE TABLE `a` (`location` point SRID NOT NULL);"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for unclosed table definition",
+			args:    args{src: "CREATE TABLE `a` (`id` int,"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for key on undefined column",
+			args:    args{src: "CREATE TABLE `a` (`id` int, PRIMARY KEY (`x`));"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for AUTO_INCREMENT option without number",
+			args:    args{src: "CREATE TABLE `a` (`id` int AUTO_INCREMENT) AUTO_INCREMENT=x;"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for DEFAULT without value",
+			args:    args{src: "CREATE TABLE `a` (`id` int DEFAULT);"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for foreign key on undefined column",
+			args:    args{src: "CREATE TABLE `a` (`id` int, FOREIGN KEY (`x`) REFERENCES `b` (`id`));"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for foreign key referring to undefined table",
+			args:    args{src: "CREATE TABLE `a` (`id` int, `b_id` int, FOREIGN KEY (`b_id`) REFERENCES `b` (`id`));"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for foreign key referring to undefined column",
+			args:    args{src: "CREATE TABLE `a` (`id` int, `b_id` int, FOREIGN KEY (`b_id`) REFERENCES `b` (`x`));\nCREATE TABLE `b` (`id` int);"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for REFERENCES of column referring to undefined table",
+			args:    args{src: "CREATE TABLE `a` (`id` int, `b_id` int REFERENCES `b` (`id`));"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for a foreign key added with undefined referenced column",
+			args:    args{src: "CREATE TABLE `a` (`id` int, `a_id` int);\nALTER TABLE `a` ADD FOREIGN KEY (`a_id`) REFERENCES `a` (`x`);"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for a foreign key added to undefined table",
+			args:    args{src: "ALTER TABLE `a` ADD FOREIGN KEY (`b_id`) REFERENCES `b` (`id`);"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for a foreign key added to undefined column",
+			args:    args{src: "CREATE TABLE `a` (`id` int);\nALTER TABLE `a` ADD CONSTRAINT `fk` FOREIGN KEY (`x`) REFERENCES `a` (`id`);"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseSchema(tt.args.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseSchema() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			diff := cmp.Diff(got, tt.want)
+			if diff != "" {
+				t.Errorf("parseSchema(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
@@ -1,11 +1,11 @@
 CREATE TABLE `customer` (
   `id` int(10) UNSIGNED AUTO_INCREMENT,
-  `created_at` timestamp,
+  `created_at` timestamp NOT NULL DEFAULT CURRENT_TIMESTAMP,
   `name` varchar(255) DEFAULT NULL,
   `material` JSON,
   PRIMARY KEY (`id`),
   UNIQUE KEY `name` (`name`),
-  KEY `id_name` (`id`, `name`),
+  KEY `id_name` (`id`, `name`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
 
 CREATE TABLE `product` (
@@ -17,6 +17,6 @@ CREATE TABLE `product` (
   `sale_day` Datetime DEFAULT NULL,
   PRIMARY KEY (`id`),
   UNIQUE KEY `name` (`name`),
-  KEY `id_name_stock` (`id`, `name`, `stock`)
+  KEY `id_name_stock` (`id`, `name`, `stock`),
   CONSTRAINT `owner` FOREIGN KEY (`owner`) REFERENCES `customer` (`name`)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8;
\ No newline at end of file
@@ -0,0 +1,278 @@
+package file_driver
+
+import (
+	"strings"
+)
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	// keyword or unquoted identifier, e.g. CREATE, varchar, customer
+	tokenWord
+	// identifier quoted by backticks, e.g. `customer`
+	tokenQuotedIdent
+	// string literal quoted by single or double quotes, e.g. 'active'
+	tokenString
+	// numeric literal, e.g. 10, 3.14, 1e3
+	tokenNumber
+	// any other single character, e.g. ( ) , ; . =
+	tokenSymbol
+)
+
+type token struct {
+	kind tokenKind
+	// text is the unquoted and unescaped content of the token
+	text string
+	// line and column where the token starts, both of which are 1-origin
+	line   int
+	column int
+}
+
+// is reports whether the token is the given symbol or the given keyword (case-insensitive).
+// quoted identifiers and string literals never match keywords.
+func (t token) is(s string) bool {
+	switch t.kind {
+	case tokenWord:
+		return strings.EqualFold(t.text, s)
+	case tokenSymbol:
+		return t.text == s
+	default:
+		return false
+	}
+}
+
+func (t token) isIdent() bool {
+	return t.kind == tokenWord || t.kind == tokenQuotedIdent
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "EOF"
+	case tokenQuotedIdent:
+		return "`" + t.text + "`"
+	case tokenString:
+		return "'" + t.text + "'"
+	default:
+		return t.text
+	}
+}
+
+type tokenizer struct {
+	src    []rune
+	pos    int
+	line   int
+	column int
+}
+
+func newTokenizer(src string) *tokenizer {
+	return &tokenizer{
+		src:    []rune(src),
+		line:   1,
+		column: 1,
+	}
+}
+
+// tokenize splits MySQL DDL into tokens, dropping whitespaces and comments.
+// the last token is always tokenEOF.
+func tokenize(src string) ([]token, error) {
+	t := newTokenizer(src)
+	tokens := []token{}
+	for {
+		tok, err := t.next()
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, tok)
+		if tok.kind == tokenEOF {
+			return tokens, nil
+		}
+	}
+}
+
+func (t *tokenizer) peek(offset int) rune {
+	if t.pos+offset >= len(t.src) {
+		return 0
+	}
+	return t.src[t.pos+offset]
+}
+
+func (t *tokenizer) eof() bool {
+	return t.pos >= len(t.src)
+}
+
+func (t *tokenizer) advance() rune {
+	r := t.src[t.pos]
+	t.pos++
+	if r == '\n' {
+		t.line++
+		t.column = 1
+	} else {
+		t.column++
+	}
+	return r
+}
+
+func (t *tokenizer) next() (token, error) {
+	if err := t.skipSpacesAndComments(); err != nil {
+		return token{}, err
+	}
+
+	tok := token{line: t.line, column: t.column}
+	if t.eof() {
+		tok.kind = tokenEOF
+		return tok, nil
+	}
+
+	r := t.peek(0)
+	switch {
+	case r == '`':
+		text, err := t.readQuoted('`')
+		if err != nil {
+			return token{}, err
+		}
+		tok.kind = tokenQuotedIdent
+		tok.text = text
+	case r == '\'' || r == '"':
+		text, err := t.readQuoted(r)
+		if err != nil {
+			return token{}, err
+		}
+		tok.kind = tokenString
+		tok.text = text
+	case isDigit(r) || (r == '.' && isDigit(t.peek(1))):
+		tok.kind = tokenNumber
+		tok.text = t.readNumber()
+	case isWordRune(r):
+		tok.kind = tokenWord
+		tok.text = t.readWord()
+	default:
+		tok.kind = tokenSymbol
+		tok.text = string(t.advance())
+	}
+	return tok, nil
+}
+
+func (t *tokenizer) skipSpacesAndComments() error {
+	for !t.eof() {
+		r := t.peek(0)
+		switch {
+		case isSpace(r):
+			t.advance()
+		case r == '#':
+			t.skipLine()
+		case r == '-' && t.peek(1) == '-' && (isSpace(t.peek(2)) || t.peek(2) == 0):
+			t.skipLine()
+		case r == '/' && t.peek(1) == '*':
+			line, column := t.line, t.column
+			t.advance()
+			t.advance()
+			for {
+				if t.eof() {
+					return newParseError(token{kind: tokenSymbol, text: "/*", line: line, column: column}, nil, "unterminated comment")
+				}
+				if t.peek(0) == '*' && t.peek(1) == '/' {
+					t.advance()
+					t.advance()
+					break
+				}
+				t.advance()
+			}
+		default:
+			return nil
+		}
+	}
+	return nil
+}
+
+func (t *tokenizer) skipLine() {
+	for !t.eof() && t.peek(0) != '\n' {
+		t.advance()
+	}
+}
+
+// readQuoted reads a token quoted by q.
+// a doubled quote is read as the quote itself, and backslash escapes are resolved in string literals.
+func (t *tokenizer) readQuoted(q rune) (string, error) {
+	line, column := t.line, t.column
+	t.advance()
+	var sb strings.Builder
+	for {
+		if t.eof() {
+			return "", newParseError(token{kind: tokenSymbol, text: string(q), line: line, column: column}, nil, "unterminated quote")
+		}
+		r := t.advance()
+		switch {
+		case r == q && t.peek(0) == q:
+			t.advance()
+			sb.WriteRune(q)
+		case r == q:
+			return sb.String(), nil
+		case r == '\\' && q != '`' && !t.eof():
+			sb.WriteRune(unescape(t.advance()))
+		default:
+			sb.WriteRune(r)
+		}
+	}
+}
+
+func (t *tokenizer) readNumber() string {
+	start := t.pos
+	for !t.eof() && (isDigit(t.peek(0)) || t.peek(0) == '.') {
+		t.advance()
+	}
+	if (t.peek(0) == 'e' || t.peek(0) == 'E') &&
+		(isDigit(t.peek(1)) || ((t.peek(1) == '+' || t.peek(1) == '-') && isDigit(t.peek(2)))) {
+		t.advance()
+		t.advance()
+		for !t.eof() && isDigit(t.peek(0)) {
+			t.advance()
+		}
+	}
+	// identifiers may begin with a digit, e.g. 1st_column
+	for !t.eof() && isWordRune(t.peek(0)) {
+		t.advance()
+	}
+	return string(t.src[start:t.pos])
+}
+
+func (t *tokenizer) readWord() string {
+	start := t.pos
+	for !t.eof() && isWordRune(t.peek(0)) {
+		t.advance()
+	}
+	return string(t.src[start:t.pos])
+}
+
+func unescape(r rune) rune {
+	switch r {
+	case '0':
+		return 0
+	case 'b':
+		return '\b'
+	case 'n':
+		return '\n'
+	case 'r':
+		return '\r'
+	case 't':
+		return '\t'
+	case 'Z':
+		return '\x1a'
+	default:
+		return r
+	}
+}
+
+func isDigit(r rune) bool {
+	return '0' <= r && r <= '9'
+}
+
+func isSpace(r rune) bool {
+	return r == ' ' || r == '\t' || r == '\n' || r == '\r' || r == '\f' || r == '\v'
+}
+
+func isWordRune(r rune) bool {
+	return r == '_' || r == '$' || isDigit(r) ||
+		('a' <= r && r <= 'z') || ('A' <= r && r <= 'Z') || r > 0x7f
+}
@@ -0,0 +1,100 @@
+package file_driver
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func Test_tokenize(t *testing.T) {
+	type args struct {
+		src string
+	}
+	tests := []struct {
+		name    string
+		args    args
+		want    []token
+		wantErr bool
+	}{
+		{
+			name: "split words, quoted identifiers, numbers and symbols with their positions",
+			args: args{src: "CREATE TABLE `t` (\n\t`id` int(10),"},
+			want: []token{
+				{kind: tokenWord, text: "CREATE", line: 1, column: 1},
+				{kind: tokenWord, text: "TABLE", line: 1, column: 8},
+				{kind: tokenQuotedIdent, text: "t", line: 1, column: 14},
+				{kind: tokenSymbol, text: "(", line: 1, column: 18},
+				{kind: tokenQuotedIdent, text: "id", line: 2, column: 2},
+				{kind: tokenWord, text: "int", line: 2, column: 7},
+				{kind: tokenSymbol, text: "(", line: 2, column: 10},
+				{kind: tokenNumber, text: "10", line: 2, column: 11},
+				{kind: tokenSymbol, text: ")", line: 2, column: 13},
+				{kind: tokenSymbol, text: ",", line: 2, column: 14},
+				{kind: tokenEOF, line: 2, column: 15},
+			},
+		},
+		{
+			name: "read string literals containing commas, backticks, escaped and doubled quotes",
+			args: args{src: `'a, b' 'it''s' 'c\'d' "e` + "`" + `f"`},
+			want: []token{
+				{kind: tokenString, text: "a, b", line: 1, column: 1},
+				{kind: tokenString, text: "it's", line: 1, column: 8},
+				{kind: tokenString, text: "c'd", line: 1, column: 16},
+				{kind: tokenString, text: "e`f", line: 1, column: 23},
+				{kind: tokenEOF, line: 1, column: 28},
+			},
+		},
+		{
+			name: "read quoted identifiers containing doubled backticks and spaces",
+			args: args{src: "`a``b c`"},
+			want: []token{
+				{kind: tokenQuotedIdent, text: "a`b c", line: 1, column: 1},
+				{kind: tokenEOF, line: 1, column: 9},
+			},
+		},
+		{
+			name: "drop block, double-dash and hash comments",
+			args: args{src: "/* a,\n b */ x -- c, d\ny # e\n--\nz"},
+			want: []token{
+				{kind: tokenWord, text: "x", line: 2, column: 7},
+				{kind: tokenWord, text: "y", line: 3, column: 1},
+				{kind: tokenWord, text: "z", line: 5, column: 1},
+				{kind: tokenEOF, line: 5, column: 2},
+			},
+		},
+		{
+			name: "do not treat double dash without a following space as a comment",
+			args: args{src: "1--1"},
+			want: []token{
+				{kind: tokenNumber, text: "1", line: 1, column: 1},
+				{kind: tokenSymbol, text: "-", line: 1, column: 2},
+				{kind: tokenSymbol, text: "-", line: 1, column: 3},
+				{kind: tokenNumber, text: "1", line: 1, column: 4},
+				{kind: tokenEOF, line: 1, column: 5},
+			},
+		},
+		{
+			name:    "return error for unterminated string literal",
+			args:    args{src: "DEFAULT 'abc"},
+			wantErr: true,
+		},
+		{
+			name:    "return error for unterminated comment",
+			args:    args{src: "/* abc"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tokenize(tt.args.src)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("tokenize() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			diff := cmp.Diff(got, tt.want, cmp.AllowUnexported(token{}))
+			if diff != "" {
+				t.Errorf("tokenize(); -got, +want\n%v", diff)
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package usecase
 
 import (
+	"io"
+	"math/rand"
+
 	"github.com/canalun/sqloth/domain/driver"
 	"github.com/canalun/sqloth/domain/model"
 )
@@ -15,14 +18,17 @@ func NewUsecase(driver driver.Driver) Usecase {
 	}
 }
 
-//TODO: refactoring the entire
-func (u Usecase) GenerateQueryOfDummyData(num int) []string {
-	schema := u.driver.GetSchema()
-
-	columnGraph := model.GenerateColumnGraph(schema)
-	valuesForColumns := model.GenerateValuesForColumns(columnGraph, num)
-	recordsForTables := model.GenerateRecordsForTables(valuesForColumns, schema, num)
-	queries := model.GenerateQuery(recordsForTables, schema)
+// GenerateDummyData writes the dummy data for the schema to w, in the format of the option.
+func (u Usecase) GenerateDummyData(w io.Writer, rn model.RecordNumber, opt model.Option) error {
+	schema, err := u.driver.GetSchema()
+	if err != nil {
+		return err
+	}
+	if err := rn.Validate(schema); err != nil {
+		return err
+	}
 
-	return queries
+	// every random value comes from r, so that the same seed gives the same output
+	r := rand.New(rand.NewSource(opt.Seed))
+	return model.WriteDummyData(w, r, schema, rn, opt)
 }
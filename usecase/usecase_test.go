@@ -1,6 +1,9 @@
 package usecase
 
 import (
+	"bytes"
+	"errors"
+	"strings"
 	"testing"
 
 	"github.com/canalun/sqloth/domain/driver"
@@ -10,18 +13,20 @@ import (
 	"github.com/google/go-cmp/cmp"
 )
 
-func TestGenerateQueryOfDummyData(t *testing.T) {
+func TestGenerateDummyData(t *testing.T) {
 	type fields struct {
 		driver func(ctrl *gomock.Controller) driver.Driver
 	}
 	type args struct {
-		num int
+		rn  model.RecordNumber
+		opt model.Option
 	}
 	tests := []struct {
 		name     string
 		fields   fields
 		args     args
-		assertFn func([]string)
+		assertFn func(string)
+		wantErr  bool
 	}{
 		{
 			name: "can generate query of dummy data from sql schema file with constraints",
@@ -98,23 +103,34 @@ func TestGenerateQueryOfDummyData(t *testing.T) {
 								},
 							},
 						},
-					})
+					}, nil)
 					return m
 				},
 			},
-			args: args{num: 3},
+			args: args{rn: model.NewRecordNumber(3), opt: model.NewOption()},
 			//TODO: mod assertFn
-			assertFn: func(s []string) {
-				diff := cmp.Diff(s[0], "SET foreign_key_checks = 0;")
-				if diff != "" {
-					t.Errorf(diff)
+			assertFn: func(s string) {
+				if !strings.HasPrefix(s, "SET foreign_key_checks = 0;\n\n") {
+					t.Errorf("output does not begin with disabling foreign key checks; %q", s)
 				}
-				diff = cmp.Diff(s[len(s)-1], "SET foreign_key_checks = 1;")
-				if diff != "" {
-					t.Errorf(diff)
+				if !strings.HasSuffix(s, "SET foreign_key_checks = 1;\n\n") {
+					t.Errorf("output does not end with enabling foreign key checks; %q", s)
 				}
 			},
 		},
+		{
+			name: "return error from the driver as it is",
+			fields: fields{
+				driver: func(ctrl *gomock.Controller) driver.Driver {
+					m := mock_driver.NewMockDriver(ctrl)
+					m.EXPECT().GetSchema().Return(model.Schema{}, errors.New("broken schema"))
+					return m
+				},
+			},
+			args:     args{rn: model.NewRecordNumber(3), opt: model.NewOption()},
+			assertFn: func(s string) {},
+			wantErr:  true,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -123,8 +139,59 @@ func TestGenerateQueryOfDummyData(t *testing.T) {
 			u := Usecase{
 				driver: tt.fields.driver(ctrl),
 			}
-			got := u.GenerateQueryOfDummyData(tt.args.num)
-			tt.assertFn(got)
+			var buf bytes.Buffer
+			err := u.GenerateDummyData(&buf, tt.args.rn, tt.args.opt)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("GenerateDummyData() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			tt.assertFn(buf.String())
 		})
 	}
 }
+
+func TestGenerateDummyData_Seed(t *testing.T) {
+	schema := model.Schema{
+		Tables: []model.Table{
+			{
+				Name: "customer",
+				Columns: []model.Column{
+					{Name: "id", FullName: "customer.id", Type: model.ColumnType{Base: model.Int}, AutoIncrement: true, NotNull: true},
+					{Name: "name", FullName: "customer.name", Type: model.ColumnType{Base: model.Varchar, Param: 8}},
+				},
+				PrimaryKey: model.Key{"id"},
+				UniqueKeys: []model.Key{{"name"}},
+			},
+			{
+				Name: "product",
+				Columns: []model.Column{
+					{Name: "owner", FullName: "product.owner", Type: model.ColumnType{Base: model.Varchar, Param: 8}, Constraints: []model.Constraint{{TableName: "customer", ColumnName: "name"}}},
+					{Name: "sale_day", FullName: "product.sale_day", Type: model.ColumnType{Base: model.Datetime}, Default: "NULL", HasDefault: true},
+				},
+			},
+		},
+	}
+	generate := func(seed int64) string {
+		ctrl := gomock.NewController(t)
+		defer ctrl.Finish()
+		m := mock_driver.NewMockDriver(ctrl)
+		m.EXPECT().GetSchema().Return(schema, nil)
+		opt := model.NewOption()
+		opt.Cardinality = model.Cardinality{Kind: model.Skewed}
+		opt.NullRate = 0.3
+		opt.DefaultRate = 0.3
+		opt.Seed = seed
+		var buf bytes.Buffer
+		if err := NewUsecase(m).GenerateDummyData(&buf, model.NewRecordNumber(20), opt); err != nil {
+			t.Fatalf("GenerateDummyData() error = %v", err)
+		}
+		return buf.String()
+	}
+
+	if diff := cmp.Diff(generate(42), generate(42)); diff != "" {
+		t.Errorf("GenerateDummyData() with the same seed; -got, +want\n%v", diff)
+	}
+	if cmp.Equal(generate(42), generate(43)) {
+		t.Errorf("GenerateDummyData() with different seeds gives the same output")
+	}
+}